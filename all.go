@@ -0,0 +1,66 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DoAll retries all fns concurrently, each with its own retry budget
+// configured by opts, and waits until every one of them has finished. The
+// results are returned in the order of fns; the result of a function that
+// failed is the zero value of T.
+//
+// By default all functions run at the same time. Use
+// WithMaxConcurrentAttempts to limit the number of functions in flight.
+//
+// If any function fails, DoAll returns the results together with an error
+// joining the errors of all failed functions.
+//
+// Example:
+//
+//	pages, err := retry.DoAll(ctx, []func() (Page, error){
+//	    fetchPage1, fetchPage2, fetchPage3,
+//	}, retry.WithAttempts(3), retry.WithMaxConcurrentAttempts(2))
+func DoAll[T any](ctx context.Context, fns []func() (T, error), opts ...Option) ([]T, error) {
+	rc := NewRetry(opts...)
+
+	var sem chan struct{}
+	if rc.maxConcurrent > 0 {
+		sem = make(chan struct{}, rc.maxConcurrent)
+	}
+
+	results := make([]T, len(fns))
+	errs := make([]error, len(fns))
+
+	var wg sync.WaitGroup
+	for i, fn := range fns {
+		if sem != nil {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				errs[i] = fmt.Errorf("operation %d: context canceled before start: %w", i, ctx.Err())
+				continue
+			}
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if sem != nil {
+				defer func() { <-sem }()
+			}
+
+			data, err := Do(ctx, rc, RetryFunc[T](fn))
+			if err != nil {
+				errs[i] = fmt.Errorf("operation %d: %w", i, err)
+				return
+			}
+			results[i] = data
+		}()
+	}
+	wg.Wait()
+
+	return results, errors.Join(errs...)
+}
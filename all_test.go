@@ -0,0 +1,91 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// concurrencyProbe counts the functions running at the same time and
+// remembers the highest count.
+type concurrencyProbe struct {
+	current atomic.Int32
+	peak    atomic.Int32
+}
+
+func (p *concurrencyProbe) enter() {
+	n := p.current.Add(1)
+	for {
+		peak := p.peak.Load()
+		if n <= peak || p.peak.CompareAndSwap(peak, n) {
+			return
+		}
+	}
+}
+
+func (p *concurrencyProbe) leave() {
+	p.current.Add(-1)
+}
+
+// TestDoAllMaxConcurrentAttempts verifies that the number of functions in
+// flight never exceeds the configured limit.
+func TestDoAllMaxConcurrentAttempts(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name  string
+		limit int
+		peak  int32
+	}{
+		{"sequential", 1, 1},
+		{"limited", 3, 3},
+		{"unlimited", 0, 8},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			probe := &concurrencyProbe{}
+			fns := make([]func() (int, error), 8)
+			for i := range fns {
+				fns[i] = func() (int, error) {
+					probe.enter()
+					defer probe.leave()
+					time.Sleep(20 * time.Millisecond)
+					return i, nil
+				}
+			}
+
+			results, err := DoAll(context.Background(), fns, WithMaxConcurrentAttempts(tc.limit))
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !slices.Equal(results, []int{0, 1, 2, 3, 4, 5, 6, 7}) {
+				t.Errorf("expected results in input order, got %v", results)
+			}
+			if got := probe.peak.Load(); got != tc.peak {
+				t.Errorf("expected peak concurrency %d, got %d", tc.peak, got)
+			}
+		})
+	}
+}
+
+// TestDoAllErrors verifies that errors of failed functions are joined while
+// the results of successful ones are kept.
+func TestDoAllErrors(t *testing.T) {
+	t.Parallel()
+	target := errors.New("fail")
+	results, err := DoAll(context.Background(), []func() (string, error){
+		func() (string, error) { return "a", nil },
+		func() (string, error) { return "", target },
+	}, WithAttempts(2), WithDelay(time.Millisecond))
+
+	if !errors.Is(err, target) || !errors.Is(err, ErrExhausted) {
+		t.Errorf("expected joined exhausted error, got %v", err)
+	}
+	if !slices.Equal(results, []string{"a", ""}) {
+		t.Errorf("expected results [a \"\"], got %q", results)
+	}
+}
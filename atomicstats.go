@@ -0,0 +1,88 @@
+package retry
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// AtomicStats aggregates statistics over all Do calls of one or more
+// RetryConfigs, e.g. for exporting metrics of a heavily used client. It is
+// updated with atomic operations only, so concurrent retry loops do not
+// contend on a lock. The zero value is ready to use; pass it to
+// WithAtomicStats.
+type AtomicStats struct {
+	calls      atomic.Int64 // Retry loops started
+	attempts   atomic.Int64 // Attempts started
+	successes  atomic.Int64 // Retry loops that succeeded
+	failures   atomic.Int64 // Retry loops that failed
+	totalDelay atomic.Int64 // Sum of the delays in nanoseconds
+	lastDelay  atomic.Int64 // Most recent delay in nanoseconds
+}
+
+// WithAtomicStats records the calls, attempts, outcomes and delays of every
+// Do call in s. The same AtomicStats may be shared by many configurations.
+//
+// Example:
+//
+//	var stats retry.AtomicStats
+//	rc := retry.NewRetry(retry.WithAtomicStats(&stats))
+//	// later, e.g. in a metrics collector
+//	attempts.Set(float64(stats.Attempts()))
+func WithAtomicStats(s *AtomicStats) Option {
+	return func(rc *RetryConfig) {
+		rc.stats = s
+	}
+}
+
+// Calls returns the number of retry loops started.
+func (s *AtomicStats) Calls() int64 { return s.calls.Load() }
+
+// Attempts returns the number of attempts started across all retry loops.
+func (s *AtomicStats) Attempts() int64 { return s.attempts.Load() }
+
+// Successes returns the number of retry loops that succeeded.
+func (s *AtomicStats) Successes() int64 { return s.successes.Load() }
+
+// Failures returns the number of retry loops that returned an error.
+func (s *AtomicStats) Failures() int64 { return s.failures.Load() }
+
+// TotalDelay returns the sum of the delays scheduled between attempts.
+func (s *AtomicStats) TotalDelay() time.Duration { return time.Duration(s.totalDelay.Load()) }
+
+// LastDelay returns the most recently scheduled delay, or 0 if there was
+// none yet.
+func (s *AtomicStats) LastDelay() time.Duration { return time.Duration(s.lastDelay.Load()) }
+
+// recordCall counts a started retry loop. Like all record methods it does
+// nothing on a nil AtomicStats.
+func (s *AtomicStats) recordCall() {
+	if s != nil {
+		s.calls.Add(1)
+	}
+}
+
+// recordAttempt counts a started attempt.
+func (s *AtomicStats) recordAttempt() {
+	if s != nil {
+		s.attempts.Add(1)
+	}
+}
+
+// recordDelay adds a scheduled delay.
+func (s *AtomicStats) recordDelay(d time.Duration) {
+	if s != nil {
+		s.totalDelay.Add(int64(d))
+		s.lastDelay.Store(int64(d))
+	}
+}
+
+// recordResult counts the outcome of a retry loop.
+func (s *AtomicStats) recordResult(err error) {
+	switch {
+	case s == nil:
+	case err == nil:
+		s.successes.Add(1)
+	default:
+		s.failures.Add(1)
+	}
+}
@@ -0,0 +1,102 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestAtomicStats verifies that concurrent retry loops sharing one
+// AtomicStats are counted exactly.
+func TestAtomicStats(t *testing.T) {
+	t.Parallel()
+	var stats AtomicStats
+	rc := NewRetry(WithAttempts(2), WithDelay(time.Millisecond), WithAtomicStats(&stats))
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = Do(context.Background(), rc, func() (int, error) {
+				if i%2 == 0 {
+					return 0, errors.New("fail")
+				}
+				return i, nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	if stats.Calls() != 50 || stats.Successes() != 25 || stats.Failures() != 25 {
+		t.Errorf("expected 50 calls with 25 successes and failures, got %d, %d, %d",
+			stats.Calls(), stats.Successes(), stats.Failures())
+	}
+	if stats.Attempts() != 75 {
+		t.Errorf("expected 75 attempts, got %d", stats.Attempts())
+	}
+	if stats.TotalDelay() != 25*time.Millisecond || stats.LastDelay() != time.Millisecond {
+		t.Errorf("expected 25ms total and 1ms last delay, got %v and %v", stats.TotalDelay(), stats.LastDelay())
+	}
+}
+
+// mutexStats is a lock-based equivalent of AtomicStats used as the
+// baseline of the benchmarks.
+type mutexStats struct {
+	mu         sync.Mutex
+	attempts   int64
+	totalDelay time.Duration
+	lastDelay  time.Duration
+}
+
+func (s *mutexStats) record(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts++
+	s.totalDelay += d
+	s.lastDelay = d
+}
+
+// benchmarkGoroutines is the number of goroutines updating the statistics
+// concurrently in the benchmarks.
+const benchmarkGoroutines = 1000
+
+// runConcurrently calls record b.N times spread over benchmarkGoroutines
+// goroutines.
+func runConcurrently(b *testing.B, record func()) {
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var wg sync.WaitGroup
+	per := b.N/benchmarkGoroutines + 1
+	for range benchmarkGoroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range per {
+				record()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// BenchmarkAtomicStats measures AtomicStats under heavy contention.
+func BenchmarkAtomicStats(b *testing.B) {
+	var stats AtomicStats
+	runConcurrently(b, func() {
+		stats.recordAttempt()
+		stats.recordDelay(time.Millisecond)
+	})
+}
+
+// BenchmarkMutexStats measures the mutex-based baseline under the same
+// contention.
+func BenchmarkMutexStats(b *testing.B) {
+	var stats mutexStats
+	runConcurrently(b, func() {
+		stats.record(time.Millisecond)
+	})
+}
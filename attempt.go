@@ -0,0 +1,31 @@
+package retry
+
+import "context"
+
+// RetryFuncWithAttempt defines the signature for retryable operations that
+// receive the number of the current attempt, starting at 1.
+type RetryFuncWithAttempt[T any] func(attempt int) (T, error)
+
+// DoWithAttempt executes fn like Do, but passes the number of the current
+// attempt, starting at 1, so the operation can vary its behavior per attempt
+// without keeping its own counter. Attempts repeated after a SoftRetry keep
+// their number.
+//
+// Example:
+//
+//	resp, err := retry.DoWithAttempt(ctx, rc, func(attempt int) (*http.Response, error) {
+//	    endpoint := primaryURL
+//	    if attempt >= 3 {
+//	        endpoint = fallbackURL
+//	    }
+//	    req, _ := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+//	    req.Header.Set("X-Attempt", strconv.Itoa(attempt))
+//	    return http.DefaultClient.Do(req)
+//	})
+func DoWithAttempt[T any](ctx context.Context, rc *RetryConfig, fn RetryFuncWithAttempt[T]) (T, error) {
+	tracker := &attemptTracker{}
+
+	return do(ctx, rc, func() (T, error) {
+		return fn(tracker.current())
+	}, loopState{observer: tracker})
+}
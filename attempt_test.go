@@ -0,0 +1,88 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestDoWithAttempt verifies that every attempt receives its number and
+// that soft retries keep the number of the attempt they repeat.
+func TestDoWithAttempt(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		errs     []error
+		expected []int
+	}{
+		{name: "first attempt succeeds", expected: []int{1}},
+		{
+			name:     "success on the third attempt",
+			errs:     []error{errors.New("fail"), errors.New("fail")},
+			expected: []int{1, 2, 3},
+		},
+		{
+			name:     "soft retry",
+			errs:     []error{SoftRetry(errors.New("not ready")), errors.New("fail")},
+			expected: []int{1, 1, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var seen []int
+			result, err := DoWithAttempt(context.Background(), NewRetry(WithDelay(time.Millisecond)), func(attempt int) (int, error) {
+				seen = append(seen, attempt)
+				if len(seen) <= len(tc.errs) {
+					return 0, tc.errs[len(seen)-1]
+				}
+				return attempt, nil
+			})
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !slices.Equal(seen, tc.expected) {
+				t.Errorf("expected attempts %v, got %v", tc.expected, seen)
+			}
+			if want := tc.expected[len(tc.expected)-1]; result != want {
+				t.Errorf("expected result %d, got %d", want, result)
+			}
+		})
+	}
+}
+
+// TestDoWithAttemptMaxAttemptDuration verifies that the attempt number can
+// be read while attempts run in their own goroutines. Run with -race.
+func TestDoWithAttemptMaxAttemptDuration(t *testing.T) {
+	t.Parallel()
+	rc := NewRetry(
+		WithAttempts(3),
+		WithDelay(time.Millisecond),
+		WithMaxAttemptDuration(20*time.Millisecond),
+	)
+
+	// Abandoned attempts are not waited for, so nothing orders their read
+	// of the attempt number before the start of the next attempt.
+	var seen atomic.Int64
+	_, err := DoWithAttempt(context.Background(), rc, func(attempt int) (int, error) {
+		seen.Add(1)
+		if attempt < 3 {
+			time.Sleep(50 * time.Millisecond)
+			return 0, errors.New("fail")
+		}
+		return attempt, nil
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := seen.Load(); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+}
@@ -0,0 +1,8 @@
+module github.com/1amDudman/try-again-go/awss3
+
+go 1.24.4
+
+require (
+	github.com/1amDudman/try-again-go v0.1.0
+	github.com/aws/smithy-go v1.28.2
+)
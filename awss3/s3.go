@@ -0,0 +1,77 @@
+// Package awss3 provides retry classification for AWS S3 operations made
+// with the AWS SDK for Go v2. It lives in its own module so that the AWS
+// dependencies are only pulled in by users who need them.
+package awss3
+
+import (
+	"errors"
+	"net/http"
+
+	retry "github.com/1amDudman/try-again-go"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// transientCodes lists the S3 error codes that are worth retrying.
+var transientCodes = map[string]bool{
+	"RequestTimeout":     true,
+	"InternalError":      true,
+	"ServiceUnavailable": true,
+	"SlowDown":           true,
+	"Throttling":         true,
+}
+
+// notFoundCodes lists the S3 error codes returned for missing objects. They
+// are retried to tolerate reads that race a PUT issued just before.
+var notFoundCodes = map[string]bool{
+	"NoSuchKey": true,
+	"NotFound":  true,
+}
+
+// WithS3Retryable returns a retry option that classifies errors returned by
+// S3 operations:
+//   - Request send errors (*smithyhttp.RequestSendError) are retried
+//   - RequestTimeout, InternalError, ServiceUnavailable, SlowDown and
+//     Throttling error codes are retried
+//   - NoSuchKey and NotFound are retried, so a read issued immediately after
+//     a PUT tolerates eventually-consistent replicas; the attempt budget
+//     bounds how long a truly missing object is polled
+//   - Other API errors, such as AccessDenied or NoSuchBucket, stop the retry
+//     loop immediately
+//   - Errors that are not S3 API errors keep the default behavior and are
+//     retried
+//
+// Example:
+//
+//	rc := retry.NewRetry(awss3.WithS3Retryable())
+//	out, err := retry.Do(ctx, rc, func() (*s3.GetObjectOutput, error) {
+//	    return client.GetObject(ctx, input)
+//	})
+func WithS3Retryable() retry.Option {
+	return retry.WithRetryIf(IsRetryable)
+}
+
+// IsRetryable reports whether err is a transient S3 error according to the
+// rules documented on WithS3Retryable.
+func IsRetryable(err error) bool {
+	var sendErr *smithyhttp.RequestSendError
+	if errors.As(err, &sendErr) {
+		return true
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		code := apiErr.ErrorCode()
+		return transientCodes[code] || notFoundCodes[code]
+	}
+
+	var respErr *smithyhttp.ResponseError
+	if errors.As(err, &respErr) {
+		status := respErr.HTTPStatusCode()
+		return status >= http.StatusInternalServerError ||
+			status == http.StatusTooManyRequests ||
+			status == http.StatusNotFound
+	}
+
+	return true
+}
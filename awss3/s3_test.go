@@ -0,0 +1,71 @@
+package awss3
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	retry "github.com/1amDudman/try-again-go"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// responseError builds a smithy response error with the given status code.
+func responseError(status int) error {
+	return &smithyhttp.ResponseError{
+		Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+		Err:      errors.New("response error"),
+	}
+}
+
+// TestIsRetryable verifies the classification of S3 errors.
+func TestIsRetryable(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"request send error", &smithyhttp.RequestSendError{Err: errors.New("dial tcp")}, true},
+		{"request timeout", &smithy.GenericAPIError{Code: "RequestTimeout"}, true},
+		{"internal error", &smithy.GenericAPIError{Code: "InternalError"}, true},
+		{"service unavailable", &smithy.GenericAPIError{Code: "ServiceUnavailable"}, true},
+		{"slow down", &smithy.GenericAPIError{Code: "SlowDown"}, true},
+		{"no such key", &smithy.GenericAPIError{Code: "NoSuchKey"}, true},
+		{"access denied", &smithy.GenericAPIError{Code: "AccessDenied"}, false},
+		{"no such bucket", &smithy.GenericAPIError{Code: "NoSuchBucket"}, false},
+		{"response 503", responseError(http.StatusServiceUnavailable), true},
+		{"response 400", responseError(http.StatusBadRequest), false},
+		{"other error", errors.New("some error"), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := IsRetryable(tc.err); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestWithS3Retryable verifies that a permanent S3 error stops Do after the
+// first attempt.
+func TestWithS3Retryable(t *testing.T) {
+	t.Parallel()
+	rc := retry.NewRetry(WithS3Retryable())
+	calls := 0
+
+	_, err := retry.Do(t.Context(), rc, func() (string, error) {
+		calls++
+		return "", &smithy.GenericAPIError{Code: "AccessDenied"}
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
@@ -0,0 +1,96 @@
+// Package azure provides retry classification for operations made with the
+// Azure SDK for Go. It lives in its own module so that the Azure
+// dependencies are only pulled in by users who need them.
+package azure
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	retry "github.com/1amDudman/try-again-go"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+)
+
+// transientCodes lists the HTTP status codes that are worth retrying.
+var transientCodes = map[int]bool{
+	http.StatusRequestTimeout:      true,
+	http.StatusTooManyRequests:     true,
+	http.StatusInternalServerError: true,
+	http.StatusBadGateway:          true,
+	http.StatusServiceUnavailable:  true,
+	http.StatusGatewayTimeout:      true,
+}
+
+// WithAzureRetryable returns a retry option that classifies errors returned
+// by Azure SDK clients. An *azcore.ResponseError is retried only for the
+// status codes 408, 429, 500, 502, 503 and 504; permanent errors such as
+// 400 Bad Request, 401 Unauthorized or 404 Not Found stop the retry loop
+// immediately. Errors that are not *azcore.ResponseError keep the default
+// behavior and are retried.
+//
+// To respect the Retry-After header of throttled responses, wrap the error
+// returned by the SDK with RetryAfter.
+//
+// Example:
+//
+//	rc := retry.NewRetry(azure.WithAzureRetryable())
+//	resp, err := retry.Do(ctx, rc, func() (azblob.DownloadStreamResponse, error) {
+//	    resp, err := client.DownloadStream(ctx, container, blob, nil)
+//	    return resp, azure.RetryAfter(err)
+//	})
+func WithAzureRetryable() retry.Option {
+	return retry.WithRetryIf(IsRetryable)
+}
+
+// IsRetryable reports whether err is a transient Azure error according to
+// the rules documented on WithAzureRetryable.
+func IsRetryable(err error) bool {
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) {
+		return transientCodes[respErr.StatusCode]
+	}
+
+	return true
+}
+
+// RetryAfter wraps an *azcore.ResponseError carrying a retry-after header
+// with retry.RetryAfter, so that Do waits for the delay requested by the
+// service. Other errors, including nil, are returned unchanged.
+//
+// The headers are checked in the order used by the Azure SDK:
+// retry-after-ms, x-ms-retry-after-ms and Retry-After.
+func RetryAfter(err error) error {
+	var respErr *azcore.ResponseError
+	if !errors.As(err, &respErr) || respErr.RawResponse == nil {
+		return err
+	}
+
+	if d, ok := retryAfterDelay(respErr.RawResponse.Header, time.Now()); ok {
+		return retry.RetryAfter(err, d)
+	}
+
+	return err
+}
+
+// retryAfterDelay extracts the delay requested by the service from the
+// response headers.
+func retryAfterDelay(h http.Header, now time.Time) (time.Duration, bool) {
+	for _, name := range []string{"retry-after-ms", "x-ms-retry-after-ms"} {
+		if ms, err := strconv.Atoi(h.Get(name)); err == nil && ms >= 0 {
+			return time.Duration(ms) * time.Millisecond, true
+		}
+	}
+
+	value := h.Get("Retry-After")
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
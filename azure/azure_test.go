@@ -0,0 +1,105 @@
+package azure
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	retry "github.com/1amDudman/try-again-go"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+)
+
+// responseError builds an Azure response error with the given status code
+// and response headers.
+func responseError(status int, header http.Header) *azcore.ResponseError {
+	return &azcore.ResponseError{
+		StatusCode:  status,
+		RawResponse: &http.Response{StatusCode: status, Header: header},
+	}
+}
+
+// TestIsRetryable verifies the classification of Azure response errors.
+func TestIsRetryable(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"too many requests", responseError(http.StatusTooManyRequests, nil), true},
+		{"internal server error", responseError(http.StatusInternalServerError, nil), true},
+		{"service unavailable", responseError(http.StatusServiceUnavailable, nil), true},
+		{"bad request", responseError(http.StatusBadRequest, nil), false},
+		{"unauthorized", responseError(http.StatusUnauthorized, nil), false},
+		{"other error", errors.New("connection reset"), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := IsRetryable(tc.err); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestRetryAfterDelay verifies that the retry-after headers are parsed in
+// the documented order.
+func TestRetryAfterDelay(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		header   http.Header
+		expected time.Duration
+		ok       bool
+	}{
+		{"none", http.Header{}, 0, false},
+		{"milliseconds", http.Header{"Retry-After-Ms": {"250"}, "Retry-After": {"5"}}, 250 * time.Millisecond, true},
+		{"ms prefixed", http.Header{"X-Ms-Retry-After-Ms": {"500"}}, 500 * time.Millisecond, true},
+		{"seconds", http.Header{"Retry-After": {"5"}}, 5 * time.Second, true},
+		{"http date", http.Header{"Retry-After": {now.Add(time.Minute).Format(http.TimeFormat)}}, time.Minute, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			d, ok := retryAfterDelay(tc.header, now)
+			if d != tc.expected || ok != tc.ok {
+				t.Errorf("expected (%v, %v), got (%v, %v)", tc.expected, tc.ok, d, ok)
+			}
+		})
+	}
+}
+
+// TestWithAzureRetryable verifies that a throttled request is retried after
+// the delay requested by the service and that permanent errors stop Do.
+func TestWithAzureRetryable(t *testing.T) {
+	t.Parallel()
+	rc := retry.NewRetry(WithAzureRetryable(), retry.WithDelay(time.Hour))
+	calls := 0
+
+	start := time.Now()
+	_, err := retry.Do(t.Context(), rc, func() (string, error) {
+		calls++
+		if calls == 1 {
+			return "", RetryAfter(responseError(http.StatusTooManyRequests, http.Header{"Retry-After-Ms": {"10"}}))
+		}
+		return "", responseError(http.StatusUnauthorized, nil)
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected Retry-After delay to be used, took %v", elapsed)
+	}
+}
@@ -0,0 +1,14 @@
+module github.com/1amDudman/try-again-go/azure
+
+go 1.24.4
+
+require (
+	github.com/1amDudman/try-again-go v0.1.0
+	github.com/Azure/azure-sdk-for-go/sdk/azcore v1.21.0
+)
+
+require (
+	github.com/Azure/azure-sdk-for-go/sdk/internal v1.12.0 // indirect
+	golang.org/x/net v0.50.0 // indirect
+	golang.org/x/text v0.34.0 // indirect
+)
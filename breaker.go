@@ -0,0 +1,233 @@
+package retry
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when a circuit breaker rejects an attempt.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// Circuit breaker states reported by CircuitBreaker.State and passed to
+// transition callbacks.
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half-open"
+)
+
+// CircuitBreaker stops retry loops from calling an operation that keeps
+// failing, giving the downstream service time to recover. Implementations
+// must be safe for concurrent use, as a breaker is usually shared by many
+// Do calls.
+type CircuitBreaker interface {
+	// Allow reports whether an attempt may be made. It returns an error
+	// wrapping ErrCircuitOpen if not.
+	Allow() error
+
+	// RecordSuccess and RecordFailure report the outcome of an attempt
+	// that was allowed.
+	RecordSuccess()
+	RecordFailure()
+
+	// State returns the current state, e.g. StateClosed.
+	State() string
+
+	// OnTransition registers a function under key that is called with the
+	// old and the new state whenever the state changes. Registering under
+	// a key that is already in use replaces its function, and a nil fn
+	// removes it; functions under other keys, e.g. those of other
+	// RetryConfigs sharing the breaker, stay registered.
+	OnTransition(key any, fn func(from, to string))
+}
+
+// WithCircuitBreaker checks cb before every attempt and reports the outcome
+// of every attempt to it. While the breaker is open Do returns an error
+// wrapping ErrCircuitOpen without calling the retry function. Errors marked
+// with IgnoreError or SuccessOnError count as successes.
+//
+// Example:
+//
+//	breaker := retry.NewCountingCircuitBreaker(5, 30*time.Second)
+//	rc := retry.NewRetry(retry.WithCircuitBreaker(breaker))
+func WithCircuitBreaker(cb CircuitBreaker) Option {
+	return func(rc *RetryConfig) {
+		rc.breaker = cb
+	}
+}
+
+// WithCircuitBreakerTransition sets a callback on the circuit breaker set
+// with WithCircuitBreaker that is called on every state change, e.g. from
+// StateClosed to StateOpen. The options may be given in any order. When
+// several RetryConfigs share a breaker, the callbacks of all of them are
+// called.
+//
+// The callback is registered on the breaker once per Option value, however
+// many configs are created with it. When configs sharing a breaker are
+// created per request, create the Option once, e.g. in a package-level
+// variable, so that the breaker does not collect a callback per request.
+//
+// Example:
+//
+//	retry.NewRetry(
+//	    retry.WithCircuitBreaker(breaker),
+//	    retry.WithCircuitBreakerTransition(func(from, to string) {
+//	        log.Printf("circuit breaker %s -> %s", from, to)
+//	    }),
+//	)
+func WithCircuitBreakerTransition(fn func(from, to string)) Option {
+	hook := &transitionHook{fn: fn}
+
+	return func(rc *RetryConfig) {
+		rc.breakerTransition = hook
+	}
+}
+
+// transitionHook is a callback set with WithCircuitBreakerTransition. Its
+// address is the key the callback is registered under on the breaker.
+type transitionHook struct {
+	fn func(from, to string)
+}
+
+// keyedTransition is a transition callback registered under a key.
+type keyedTransition struct {
+	key any
+	fn  func(from, to string)
+}
+
+// CountingCircuitBreaker is a CircuitBreaker that opens after a number of
+// consecutive failures. After a cooldown it becomes half-open and allows a
+// single trial attempt: success closes it again, failure reopens it. Use
+// NewCountingCircuitBreaker() to create instances.
+type CountingCircuitBreaker struct {
+	threshold int           // Consecutive failures that open the breaker
+	cooldown  time.Duration // Time the breaker stays open
+	now       func() time.Time
+
+	mu           sync.Mutex
+	state        string
+	failures     int       // Consecutive failures while closed
+	openedAt     time.Time // When the breaker was opened
+	trial        bool      // A half-open trial attempt is in flight
+	onTransition []keyedTransition
+}
+
+// NewCountingCircuitBreaker creates a closed CountingCircuitBreaker that
+// opens after threshold consecutive failures and stays open for cooldown.
+//
+// Example:
+//
+//	breaker := retry.NewCountingCircuitBreaker(5, 30*time.Second)
+func NewCountingCircuitBreaker(threshold int, cooldown time.Duration) *CountingCircuitBreaker {
+	return &CountingCircuitBreaker{
+		threshold: max(threshold, 1),
+		cooldown:  cooldown,
+		now:       time.Now,
+		state:     StateClosed,
+	}
+}
+
+// Allow implements CircuitBreaker.
+func (b *CountingCircuitBreaker) Allow() error {
+	b.mu.Lock()
+	notify := b.transitionIfCooledDown()
+	allowed := b.state == StateClosed || (b.state == StateHalfOpen && !b.trial)
+	if b.state == StateHalfOpen && allowed {
+		b.trial = true
+	}
+	b.mu.Unlock()
+
+	notify()
+	if !allowed {
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+// RecordSuccess implements CircuitBreaker.
+func (b *CountingCircuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	b.failures = 0
+	b.trial = false
+	notify := b.setState(StateClosed)
+	b.mu.Unlock()
+
+	notify()
+}
+
+// RecordFailure implements CircuitBreaker.
+func (b *CountingCircuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	notify := func() {}
+	switch b.state {
+	case StateHalfOpen:
+		b.trial = false
+		b.openedAt = b.now()
+		notify = b.setState(StateOpen)
+	case StateClosed:
+		b.failures++
+		if b.failures >= b.threshold {
+			b.openedAt = b.now()
+			notify = b.setState(StateOpen)
+		}
+	}
+	b.mu.Unlock()
+
+	notify()
+}
+
+// State implements CircuitBreaker.
+func (b *CountingCircuitBreaker) State() string {
+	b.mu.Lock()
+	notify := b.transitionIfCooledDown()
+	state := b.state
+	b.mu.Unlock()
+
+	notify()
+	return state
+}
+
+// OnTransition implements CircuitBreaker.
+func (b *CountingCircuitBreaker) OnTransition(key any, fn func(from, to string)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Copy on write, as notifications may still use the old slice.
+	hooks := slices.DeleteFunc(slices.Clone(b.onTransition), func(t keyedTransition) bool {
+		return t.key == key
+	})
+	if fn != nil {
+		hooks = append(hooks, keyedTransition{key: key, fn: fn})
+	}
+	b.onTransition = hooks
+}
+
+// transitionIfCooledDown moves an open breaker to half-open once the
+// cooldown has elapsed. It must be called with b.mu held.
+func (b *CountingCircuitBreaker) transitionIfCooledDown() func() {
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		b.failures = 0
+		return b.setState(StateHalfOpen)
+	}
+	return func() {}
+}
+
+// setState changes the state and returns a function that notifies the
+// transition callbacks. They are called after b.mu is released, so they may
+// use the breaker. setState must be called with b.mu held.
+func (b *CountingCircuitBreaker) setState(to string) func() {
+	from := b.state
+	if from == to {
+		return func() {}
+	}
+
+	b.state = to
+	hooks := b.onTransition
+	return func() {
+		for _, t := range hooks {
+			t.fn(from, to)
+		}
+	}
+}
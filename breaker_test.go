@@ -0,0 +1,144 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestCountingCircuitBreakerTransitions verifies that the transition
+// callback fires on every state change of the counting breaker.
+func TestCountingCircuitBreakerTransitions(t *testing.T) {
+	t.Parallel()
+	clock := &fakeClock{now: time.Date(2025, 1, 6, 12, 0, 0, 0, time.UTC)}
+	cb := NewCountingCircuitBreaker(2, time.Minute)
+	cb.now = clock.Now
+
+	var transitions []string
+	NewRetry(
+		WithCircuitBreakerTransition(func(from, to string) {
+			transitions = append(transitions, from+"->"+to)
+		}),
+		WithCircuitBreaker(cb),
+	)
+
+	cb.RecordFailure()
+	cb.RecordFailure()
+	if err := cb.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen while open, got %v", err)
+	}
+
+	clock.Advance(time.Minute)
+	if err := cb.Allow(); err != nil {
+		t.Fatalf("expected trial attempt to be allowed, got %v", err)
+	}
+	if err := cb.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected only one trial attempt while half-open, got %v", err)
+	}
+	cb.RecordFailure()
+
+	clock.Advance(time.Minute)
+	if state := cb.State(); state != StateHalfOpen {
+		t.Fatalf("expected half-open after cooldown, got %s", state)
+	}
+	cb.RecordSuccess()
+
+	expected := []string{
+		"closed->open",
+		"open->half-open",
+		"half-open->open",
+		"open->half-open",
+		"half-open->closed",
+	}
+	if !slices.Equal(transitions, expected) {
+		t.Errorf("expected transitions %v, got %v", expected, transitions)
+	}
+}
+
+// TestDoCircuitBreaker verifies that Do reports attempt outcomes to the
+// breaker and stops calling the operation once it opens.
+func TestDoCircuitBreaker(t *testing.T) {
+	t.Parallel()
+	cb := NewCountingCircuitBreaker(2, time.Hour)
+
+	var transitions []string
+	rc := NewRetry(
+		WithAttempts(5),
+		WithDelay(time.Millisecond),
+		WithCircuitBreaker(cb),
+		WithCircuitBreakerTransition(func(from, to string) {
+			transitions = append(transitions, from+"->"+to)
+		}),
+	)
+
+	calls := 0
+	_, err := Do(context.Background(), rc, func() (int, error) {
+		calls++
+		return 0, errors.New("unavailable")
+	})
+
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls before the breaker opened, got %d", calls)
+	}
+	if !slices.Equal(transitions, []string{"closed->open"}) {
+		t.Errorf("expected a single closed->open transition, got %v", transitions)
+	}
+}
+
+// TestCountingCircuitBreakerSharedTransitions verifies that every config
+// sharing a breaker keeps its transition callback.
+func TestCountingCircuitBreakerSharedTransitions(t *testing.T) {
+	t.Parallel()
+	cb := NewCountingCircuitBreaker(1, time.Minute)
+
+	var first, second []string
+	NewRetry(
+		WithCircuitBreaker(cb),
+		WithCircuitBreakerTransition(func(from, to string) { first = append(first, from+"->"+to) }),
+	)
+	NewRetry(
+		WithCircuitBreaker(cb),
+		WithCircuitBreakerTransition(func(from, to string) { second = append(second, from+"->"+to) }),
+	)
+
+	cb.RecordFailure()
+
+	want := []string{"closed->open"}
+	if !slices.Equal(first, want) || !slices.Equal(second, want) {
+		t.Errorf("expected %v for both configs, got %v and %v", want, first, second)
+	}
+}
+
+// TestCircuitBreakerTransitionRegisteredOnce verifies that configs created
+// repeatedly with the same option, as by DoAll and MustDo, register the
+// callback on the breaker only once.
+func TestCircuitBreakerTransitionRegisteredOnce(t *testing.T) {
+	t.Parallel()
+	cb := NewCountingCircuitBreaker(1, time.Minute)
+
+	var transitions []string
+	opts := []Option{
+		WithAttempts(1),
+		WithCircuitBreaker(cb),
+		WithCircuitBreakerTransition(func(from, to string) { transitions = append(transitions, from+"->"+to) }),
+	}
+	for range 10 {
+		NewRetry(opts...)
+	}
+	_ = DoVoid(context.Background(), NewRetry(opts...), func() error { return nil })
+	MustDoVoid(context.Background(), func() error { return nil }, opts...)
+
+	cb.RecordFailure()
+
+	if want := []string{"closed->open"}; !slices.Equal(transitions, want) {
+		t.Errorf("expected %v, got %v", want, transitions)
+	}
+	if got := len(cb.onTransition); got != 1 {
+		t.Errorf("expected 1 registered callback, got %d", got)
+	}
+}
@@ -0,0 +1,167 @@
+package retry
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRetryBudgetExhausted is returned when a retry budget has no tokens
+// left for another retry.
+var ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// GlobalBudgetRegistry is the package-level registry of retry budgets used
+// by WithBudgetKey.
+var GlobalBudgetRegistry = NewBudgetRegistry()
+
+// RetryBudget limits the rate of retries across all retry loops sharing it,
+// so that a failing dependency is not flooded with retries from many callers
+// at once. Every retry, i.e. every attempt after the first, takes a token;
+// tokens are refilled one per refill interval up to the capacity. First
+// attempts are never limited. It is safe for concurrent use. Use
+// NewRetryBudget() to create instances.
+type RetryBudget struct {
+	capacity int              // Maximum number of tokens
+	refill   time.Duration    // Time to regain one token
+	now      func() time.Time // Current time, replaced by a fake clock in tests
+
+	mu       sync.Mutex
+	tokens   int       // Tokens available at refilled
+	refilled time.Time // Time the tokens were last refilled
+}
+
+// NewRetryBudget creates a RetryBudget that starts with capacity tokens and
+// regains one token every refill. A non-positive refill never regains tokens.
+//
+// Example:
+//
+//	// Allow bursts of 100 retries and 10 retries per second on average.
+//	budget := retry.NewRetryBudget(100, 100*time.Millisecond)
+func NewRetryBudget(capacity int, refill time.Duration) *RetryBudget {
+	return &RetryBudget{
+		capacity: capacity,
+		refill:   refill,
+		now:      time.Now,
+		tokens:   capacity,
+		refilled: time.Now(),
+	}
+}
+
+// TryAcquire takes a token and reports whether one was available.
+func (b *RetryBudget) TryAcquire() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.update()
+	if b.tokens == 0 {
+		return false
+	}
+	b.tokens--
+
+	return true
+}
+
+// Available returns the number of tokens currently available.
+func (b *RetryBudget) Available() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.update()
+
+	return b.tokens
+}
+
+// update adds the tokens regained since the last refill. It must be called
+// with mu held.
+func (b *RetryBudget) update() {
+	now := b.now()
+	if b.refill <= 0 || b.tokens >= b.capacity {
+		b.refilled = now
+		return
+	}
+
+	regained := int(now.Sub(b.refilled) / b.refill)
+	if regained <= 0 {
+		return
+	}
+	b.tokens = min(b.tokens+regained, b.capacity)
+	b.refilled = b.refilled.Add(time.Duration(regained) * b.refill)
+}
+
+// BudgetRegistry stores retry budgets under keys, so that RetryConfigs
+// created independently by different code paths can compete for the same
+// token pool. It is safe for concurrent use. Use NewBudgetRegistry() to
+// create instances or the package-level GlobalBudgetRegistry.
+type BudgetRegistry struct {
+	mu      sync.RWMutex
+	budgets map[string]*RetryBudget
+}
+
+// NewBudgetRegistry creates an empty BudgetRegistry.
+func NewBudgetRegistry() *BudgetRegistry {
+	return &BudgetRegistry{budgets: map[string]*RetryBudget{}}
+}
+
+// Register stores budget under key, replacing any budget registered
+// before. A nil budget removes the key.
+//
+// Example:
+//
+//	func init() {
+//	    retry.GlobalBudgetRegistry.Register("payment-service", retry.NewRetryBudget(100, 100*time.Millisecond))
+//	}
+func (r *BudgetRegistry) Register(key string, budget *RetryBudget) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if budget == nil {
+		delete(r.budgets, key)
+		return
+	}
+	r.budgets[key] = budget
+}
+
+// Get returns the budget registered under key and whether there is one.
+func (r *BudgetRegistry) Get(key string) (*RetryBudget, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	budget, ok := r.budgets[key]
+
+	return budget, ok
+}
+
+// WithBudgetKey makes every retry take a token from the budget registered
+// under key in GlobalBudgetRegistry. All RetryConfigs with the same key
+// share that budget. When it is exhausted Do stops and returns an error
+// wrapping both ErrRetryBudgetExhausted and the error of the last attempt.
+// The budget is looked up on every retry, so it may be registered after
+// the config is created; while no budget is registered retries are not
+// limited.
+//
+// Example:
+//
+//	retry.GlobalBudgetRegistry.Register("payment-service", retry.NewRetryBudget(100, 100*time.Millisecond))
+//
+//	reads := retry.NewRetry(retry.WithBudgetKey("payment-service"))
+//	writes := retry.NewRetry(retry.WithAttempts(5), retry.WithBudgetKey("payment-service"))
+func WithBudgetKey(key string) Option {
+	return func(rc *RetryConfig) {
+		rc.budgetKey = key
+	}
+}
+
+// acquireBudget takes a token from the budget of rc for a retry. It reports
+// false if the budget is registered and exhausted.
+func (rc *RetryConfig) acquireBudget() bool {
+	if rc.budgetKey == "" {
+		return true
+	}
+
+	budget, ok := GlobalBudgetRegistry.Get(rc.budgetKey)
+	if !ok {
+		return true
+	}
+
+	return budget.TryAcquire()
+}
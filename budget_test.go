@@ -0,0 +1,107 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestRetryBudgetRefill verifies that tokens are taken by TryAcquire and
+// regained over time up to the capacity.
+func TestRetryBudgetRefill(t *testing.T) {
+	t.Parallel()
+	clock := &fakeClock{now: time.Date(2025, 1, 6, 12, 0, 0, 0, time.UTC)}
+	b := NewRetryBudget(2, time.Second)
+	b.now = clock.Now
+	b.refilled = clock.Now()
+
+	if !b.TryAcquire() || !b.TryAcquire() {
+		t.Fatal("expected the initial tokens to be available")
+	}
+	if b.TryAcquire() {
+		t.Fatal("expected the budget to be exhausted")
+	}
+
+	clock.Advance(1500 * time.Millisecond)
+	if got := b.Available(); got != 1 {
+		t.Errorf("expected 1 token after 1.5s, got %d", got)
+	}
+
+	clock.Advance(time.Hour)
+	if got := b.Available(); got != 2 {
+		t.Errorf("expected the tokens to be capped at 2, got %d", got)
+	}
+}
+
+// TestBudgetRegistry verifies registering, replacing and removing budgets.
+func TestBudgetRegistry(t *testing.T) {
+	t.Parallel()
+	r := NewBudgetRegistry()
+	first, second := NewRetryBudget(1, 0), NewRetryBudget(2, 0)
+
+	if _, ok := r.Get("db"); ok {
+		t.Fatal("expected no budget before registration")
+	}
+
+	r.Register("db", first)
+	r.Register("db", second)
+	if got, ok := r.Get("db"); !ok || got != second {
+		t.Errorf("expected the second budget, got %p", got)
+	}
+
+	r.Register("db", nil)
+	if _, ok := r.Get("db"); ok {
+		t.Error("expected the budget to be removed")
+	}
+}
+
+// TestDoWithBudgetKey verifies that configs with the same budget key share
+// the retries of one budget and that first attempts are not limited.
+func TestDoWithBudgetKey(t *testing.T) {
+	t.Parallel()
+	key := t.Name()
+	GlobalBudgetRegistry.Register(key, NewRetryBudget(3, 0))
+	t.Cleanup(func() { GlobalBudgetRegistry.Register(key, nil) })
+
+	errFail := errors.New("fail")
+	calls := 0
+	fn := func() (int, error) {
+		calls++
+		return 0, errFail
+	}
+
+	first := NewRetry(WithAttempts(3), WithDelay(time.Millisecond), WithBudgetKey(key))
+	second := NewRetry(WithAttempts(5), WithDelay(time.Millisecond), WithBudgetKey(key))
+
+	if _, err := Do(context.Background(), first, fn); errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("expected the first call to stay within the budget, got %v", err)
+	}
+
+	_, err := Do(context.Background(), second, fn)
+	if !errors.Is(err, ErrRetryBudgetExhausted) || !errors.Is(err, errFail) {
+		t.Errorf("expected ErrRetryBudgetExhausted wrapping the last error, got %v", err)
+	}
+
+	// 3 attempts of the first call, 2 of the second: one retry was left.
+	if calls != 5 {
+		t.Errorf("expected 5 calls, got %d", calls)
+	}
+}
+
+// TestDoWithBudgetKeyUnregistered verifies that retries are not limited
+// while no budget is registered under the key.
+func TestDoWithBudgetKeyUnregistered(t *testing.T) {
+	t.Parallel()
+	rc := NewRetry(WithAttempts(3), WithDelay(time.Millisecond), WithBudgetKey(t.Name()))
+
+	calls := 0
+	_, _ = Do(context.Background(), rc, func() (int, error) {
+		calls++
+		return 0, errors.New("fail")
+	})
+
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
@@ -0,0 +1,293 @@
+package retry
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+)
+
+// RetryBuilder provides a fluent alternative to functional options for
+// creating a RetryConfig. There is a method for every option of this
+// package; each records the corresponding option and returns the builder,
+// so calls can be chained. Options of integration packages are recorded
+// with With. Use NewRetryBuilder() to create instances and Build() to
+// obtain the validated configuration.
+//
+// Example:
+//
+//	rc, err := retry.NewRetryBuilder().
+//	    Attempts(5).
+//	    Delay(200 * time.Millisecond).
+//	    MaxDelay(10 * time.Second).
+//	    DelayType(retry.ExpBackoffWithJitter()).
+//	    Build()
+type RetryBuilder struct {
+	opts []Option // Options applied in order by Build
+}
+
+// NewRetryBuilder creates an empty RetryBuilder. Options that are not set
+// keep the defaults documented on NewRetry.
+func NewRetryBuilder() *RetryBuilder {
+	return &RetryBuilder{}
+}
+
+// With records arbitrary options, such as those provided by integration
+// packages, which have no dedicated builder method.
+func (b *RetryBuilder) With(opts ...Option) *RetryBuilder {
+	b.opts = append(b.opts, opts...)
+	return b
+}
+
+// Attempts is the builder equivalent of WithAttempts.
+func (b *RetryBuilder) Attempts(attempts int) *RetryBuilder {
+	return b.With(WithAttempts(attempts))
+}
+
+// Delay is the builder equivalent of WithDelay.
+func (b *RetryBuilder) Delay(delay time.Duration) *RetryBuilder {
+	return b.With(WithDelay(delay))
+}
+
+// MaxDelay is the builder equivalent of WithMaxDelay.
+func (b *RetryBuilder) MaxDelay(maxDelay time.Duration) *RetryBuilder {
+	return b.With(WithMaxDelay(maxDelay))
+}
+
+// MinDelay is the builder equivalent of WithMinDelay.
+func (b *RetryBuilder) MinDelay(d time.Duration) *RetryBuilder {
+	return b.With(WithMinDelay(d))
+}
+
+// DelayType is the builder equivalent of WithDelayType.
+func (b *RetryBuilder) DelayType(delayType DelayTypeFunc) *RetryBuilder {
+	return b.With(WithDelayType(delayType))
+}
+
+// Logger is the builder equivalent of WithLogger.
+func (b *RetryBuilder) Logger(logger Logger) *RetryBuilder {
+	return b.With(WithLogger(logger))
+}
+
+// OnRetry is the builder equivalent of WithOnRetry.
+func (b *RetryBuilder) OnRetry(fn OnRetryFunc) *RetryBuilder {
+	return b.With(WithOnRetry(fn))
+}
+
+// RetryIf is the builder equivalent of WithRetryIf.
+func (b *RetryBuilder) RetryIf(fn RetryIfFunc) *RetryBuilder {
+	return b.With(WithRetryIf(fn))
+}
+
+// NetworkErrorClassifier is the builder equivalent of WithNetworkErrorClassifier.
+func (b *RetryBuilder) NetworkErrorClassifier() *RetryBuilder {
+	return b.With(WithNetworkErrorClassifier())
+}
+
+// LogConfigOnStart is the builder equivalent of WithLogConfigOnStart.
+func (b *RetryBuilder) LogConfigOnStart(enabled bool) *RetryBuilder {
+	return b.With(WithLogConfigOnStart(enabled))
+}
+
+// MaxAttemptDuration is the builder equivalent of WithMaxAttemptDuration.
+func (b *RetryBuilder) MaxAttemptDuration(d time.Duration) *RetryBuilder {
+	return b.With(WithMaxAttemptDuration(d))
+}
+
+// AbortFunc is the builder equivalent of WithAbortFunc.
+func (b *RetryBuilder) AbortFunc(fn func(attempt int)) *RetryBuilder {
+	return b.With(WithAbortFunc(fn))
+}
+
+// TimeBasedAttempts is the builder equivalent of WithTimeBasedAttempts.
+func (b *RetryBuilder) TimeBasedAttempts(window, budget time.Duration) *RetryBuilder {
+	return b.With(WithTimeBasedAttempts(window, budget))
+}
+
+// DynamicAttempts is the builder equivalent of WithDynamicAttempts.
+func (b *RetryBuilder) DynamicAttempts(fn func() int) *RetryBuilder {
+	return b.With(WithDynamicAttempts(fn))
+}
+
+// DynamicDelay is the builder equivalent of WithDynamicDelay.
+func (b *RetryBuilder) DynamicDelay(fn func(attempt int) time.Duration) *RetryBuilder {
+	return b.With(WithDynamicDelay(fn))
+}
+
+// UnlimitedAttempts is the builder equivalent of WithUnlimitedAttempts.
+func (b *RetryBuilder) UnlimitedAttempts() *RetryBuilder {
+	return b.With(WithUnlimitedAttempts())
+}
+
+// FixedAttemptDelay is the builder equivalent of WithFixedAttemptDelay.
+func (b *RetryBuilder) FixedAttemptDelay(delays []time.Duration) *RetryBuilder {
+	return b.With(WithFixedAttemptDelay(delays))
+}
+
+// ErrorWindow is the builder equivalent of WithErrorWindow.
+func (b *RetryBuilder) ErrorWindow(n int, fn func(errors []error) bool) *RetryBuilder {
+	return b.With(WithErrorWindow(n, fn))
+}
+
+// OffHours is the builder equivalent of WithOffHours.
+func (b *RetryBuilder) OffHours(allowFn func(time.Time) bool) *RetryBuilder {
+	return b.With(WithOffHours(allowFn))
+}
+
+// DelayObserver is the builder equivalent of WithDelayObserver.
+func (b *RetryBuilder) DelayObserver(fn func(planned, actual time.Duration)) *RetryBuilder {
+	return b.With(WithDelayObserver(fn))
+}
+
+// RecoverPanics is the builder equivalent of WithRecoverPanics.
+func (b *RetryBuilder) RecoverPanics(enabled bool) *RetryBuilder {
+	return b.With(WithRecoverPanics(enabled))
+}
+
+// PanicHandler is the builder equivalent of WithPanicHandler.
+func (b *RetryBuilder) PanicHandler(fn func(attempt int, recovered any)) *RetryBuilder {
+	return b.With(WithPanicHandler(fn))
+}
+
+// ContextTagger is the builder equivalent of WithContextTagger.
+func (b *RetryBuilder) ContextTagger(fn func(ctx context.Context, attempt int) context.Context) *RetryBuilder {
+	return b.With(WithContextTagger(fn))
+}
+
+// ContextValues is the builder equivalent of WithContextValues.
+func (b *RetryBuilder) ContextValues(vals map[any]any) *RetryBuilder {
+	return b.With(WithContextValues(vals))
+}
+
+// ProgressWriter is the builder equivalent of WithProgressWriter.
+func (b *RetryBuilder) ProgressWriter(w io.Writer, format ProgressFormat) *RetryBuilder {
+	return b.With(WithProgressWriter(w, format))
+}
+
+// AttemptCost is the builder equivalent of WithAttemptCost.
+func (b *RetryBuilder) AttemptCost(costFn func(attempt int) float64) *RetryBuilder {
+	return b.With(WithAttemptCost(costFn))
+}
+
+// TotalCostBudget is the builder equivalent of WithTotalCostBudget.
+func (b *RetryBuilder) TotalCostBudget(budget float64) *RetryBuilder {
+	return b.With(WithTotalCostBudget(budget))
+}
+
+// FailFast is the builder equivalent of WithFailFast.
+func (b *RetryBuilder) FailFast() *RetryBuilder {
+	return b.With(WithFailFast())
+}
+
+// MaxConcurrentAttempts is the builder equivalent of WithMaxConcurrentAttempts.
+func (b *RetryBuilder) MaxConcurrentAttempts(n int) *RetryBuilder {
+	return b.With(WithMaxConcurrentAttempts(n))
+}
+
+// DeadlineMargin is the builder equivalent of WithDeadlineMargin.
+func (b *RetryBuilder) DeadlineMargin(d time.Duration) *RetryBuilder {
+	return b.With(WithDeadlineMargin(d))
+}
+
+// SleepFunc is the builder equivalent of WithSleepFunc.
+func (b *RetryBuilder) SleepFunc(fn func(time.Duration)) *RetryBuilder {
+	return b.With(WithSleepFunc(fn))
+}
+
+// AttemptFilter is the builder equivalent of WithAttemptFilter.
+func (b *RetryBuilder) AttemptFilter(fn func(attempt int, err error) bool) *RetryBuilder {
+	return b.With(WithAttemptFilter(fn))
+}
+
+// DelayCap is the builder equivalent of WithDelayCap.
+func (b *RetryBuilder) DelayCap(fn func(attempt int) time.Duration) *RetryBuilder {
+	return b.With(WithDelayCap(fn))
+}
+
+// Mutex is the builder equivalent of WithMutex.
+func (b *RetryBuilder) Mutex() *RetryBuilder {
+	return b.With(WithMutex())
+}
+
+// PerAttemptLogger is the builder equivalent of WithPerAttemptLogger.
+func (b *RetryBuilder) PerAttemptLogger(fn func(attempt int) Logger) *RetryBuilder {
+	return b.With(WithPerAttemptLogger(fn))
+}
+
+// ZeroValueTreatedAsError is the builder equivalent of WithZeroValueTreatedAsError.
+func (b *RetryBuilder) ZeroValueTreatedAsError(retryErr error) *RetryBuilder {
+	return b.With(WithZeroValueTreatedAsError(retryErr))
+}
+
+// MultiError is the builder equivalent of WithMultiError.
+func (b *RetryBuilder) MultiError() *RetryBuilder {
+	return b.With(WithMultiError())
+}
+
+// AbortOnContextValues is the builder equivalent of WithAbortOnContextValues.
+func (b *RetryBuilder) AbortOnContextValues(keys ...any) *RetryBuilder {
+	return b.With(WithAbortOnContextValues(keys...))
+}
+
+// MaxElapsedTime is the builder equivalent of WithMaxElapsedTime.
+func (b *RetryBuilder) MaxElapsedTime(d time.Duration) *RetryBuilder {
+	return b.With(WithMaxElapsedTime(d))
+}
+
+// AtomicStats is the builder equivalent of WithAtomicStats.
+func (b *RetryBuilder) AtomicStats(s *AtomicStats) *RetryBuilder {
+	return b.With(WithAtomicStats(s))
+}
+
+// CircuitBreaker is the builder equivalent of WithCircuitBreaker.
+func (b *RetryBuilder) CircuitBreaker(cb CircuitBreaker) *RetryBuilder {
+	return b.With(WithCircuitBreaker(cb))
+}
+
+// CircuitBreakerTransition is the builder equivalent of WithCircuitBreakerTransition.
+func (b *RetryBuilder) CircuitBreakerTransition(fn func(from, to string)) *RetryBuilder {
+	return b.With(WithCircuitBreakerTransition(fn))
+}
+
+// BudgetKey is the builder equivalent of WithBudgetKey.
+func (b *RetryBuilder) BudgetKey(key string) *RetryBuilder {
+	return b.With(WithBudgetKey(key))
+}
+
+// Clock is the builder equivalent of WithClock.
+func (b *RetryBuilder) Clock(c Clock) *RetryBuilder {
+	return b.With(WithClock(c))
+}
+
+// DelayMiddleware is the builder equivalent of WithDelayMiddleware.
+func (b *RetryBuilder) DelayMiddleware(mw ...DelayMiddleware) *RetryBuilder {
+	return b.With(WithDelayMiddleware(mw...))
+}
+
+// RequestID is the builder equivalent of WithRequestID.
+func (b *RetryBuilder) RequestID(id string) *RetryBuilder {
+	return b.With(WithRequestID(id))
+}
+
+// Slog is the builder equivalent of WithSlog.
+func (b *RetryBuilder) Slog(logger *slog.Logger) *RetryBuilder {
+	return b.With(WithSlog(logger))
+}
+
+// TelemetryProvider is the builder equivalent of WithTelemetryProvider.
+func (b *RetryBuilder) TelemetryProvider(tp TelemetryProvider) *RetryBuilder {
+	return b.With(WithTelemetryProvider(tp))
+}
+
+// Build applies the recorded options in order and validates the result.
+// It returns an error wrapping ErrInvalidConfig if the configuration is
+// invalid, for example when the number of attempts is not positive.
+func (b *RetryBuilder) Build() (*RetryConfig, error) {
+	rc := applyOptions(b.opts)
+	if err := rc.validate(); err != nil {
+		return nil, err
+	}
+	rc.complete()
+
+	return rc, nil
+}
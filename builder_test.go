@@ -0,0 +1,130 @@
+package retry
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestRetryBuilderBuild verifies that builder methods are applied in order
+// and produce the same configuration as the equivalent options.
+func TestRetryBuilderBuild(t *testing.T) {
+	hookCalled := false
+	rc, err := NewRetryBuilder().
+		Attempts(5).
+		Delay(200 * time.Millisecond).
+		MaxDelay(10 * time.Second).
+		DelayType(ExpBackoffWithJitter()).
+		OnRetry(func(int, error, time.Duration) { hookCalled = true }).
+		LogConfigOnStart(true).
+		MaxAttemptDuration(time.Second).
+		Build()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if rc.attempts != 5 {
+		t.Errorf("expected attempts to be 5, got %d", rc.attempts)
+	}
+
+	if rc.baseDelay != 200*time.Millisecond {
+		t.Errorf("expected baseDelay to be 200ms, got %v", rc.baseDelay)
+	}
+
+	if rc.maxDelay != 10*time.Second {
+		t.Errorf("expected maxDelay to be 10s, got %v", rc.maxDelay)
+	}
+
+	if name := funcName(rc.delayType); name != "ExpBackoffWithJitter" {
+		t.Errorf("expected ExpBackoffWithJitter strategy, got %s", name)
+	}
+
+	if !rc.logConfigOnStart {
+		t.Error("expected logConfigOnStart to be enabled")
+	}
+
+	if rc.maxAttemptDuration != time.Second {
+		t.Errorf("expected maxAttemptDuration to be 1s, got %v", rc.maxAttemptDuration)
+	}
+
+	rc.onRetry(1, nil, 0)
+	if !hookCalled {
+		t.Error("expected onRetry hook to be set")
+	}
+}
+
+// TestRetryBuilderValidation verifies that Build rejects invalid values.
+func TestRetryBuilderValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		builder *RetryBuilder
+	}{
+		{"zero attempts", NewRetryBuilder().Attempts(0)},
+		{"negative delay", NewRetryBuilder().Delay(-time.Second)},
+		{"negative max delay", NewRetryBuilder().MaxDelay(-1)},
+		{"negative max attempt duration", NewRetryBuilder().MaxAttemptDuration(-time.Second)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rc, err := tc.builder.Build()
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("expected ErrInvalidConfig, got %v", err)
+			}
+
+			if rc != nil {
+				t.Error("expected nil config on validation error")
+			}
+		})
+	}
+}
+
+// TestRetryBuilderMatchesOptions verifies that the builder methods of the
+// later options produce the same configuration as the options themselves.
+func TestRetryBuilderMatchesOptions(t *testing.T) {
+	t.Parallel()
+	clock := &fakeClock{now: time.Date(2025, 1, 6, 12, 0, 0, 0, time.UTC)}
+	retryIf := func(error) bool { return true }
+	filter := func(int, error) bool { return true }
+
+	rc, err := NewRetryBuilder().
+		UnlimitedAttempts().
+		MaxElapsedTime(time.Minute).
+		Clock(clock).
+		RetryIf(retryIf).
+		RequestID("req-1").
+		AttemptFilter(filter).
+		BudgetKey("db").
+		MultiError().
+		FailFast().
+		DeadlineMargin(time.Second).
+		Build()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := NewRetry(
+		WithUnlimitedAttempts(),
+		WithMaxElapsedTime(time.Minute),
+		WithClock(clock),
+		WithRetryIf(retryIf),
+		WithRequestID("req-1"),
+		WithAttemptFilter(filter),
+		WithBudgetKey("db"),
+		WithMultiError(),
+		WithFailFast(),
+		WithDeadlineMargin(time.Second),
+	)
+
+	if got, expected := rc.fields(), want.fields(); !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if rc.clock != want.clock || rc.requestID != want.requestID || rc.budgetKey != want.budgetKey ||
+		rc.multiError != want.multiError || rc.maxElapsed != want.maxElapsed {
+		t.Error("expected the builder to set the same fields as the options")
+	}
+}
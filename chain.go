@@ -0,0 +1,178 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+)
+
+// RetryChain composes two retry configurations that handle different error
+// classes. Each configuration keeps its own retry budget, delay strategy,
+// logger and hooks. The error class a configuration handles is defined by
+// its WithRetryIf predicate; a configuration without a predicate handles
+// every error.
+//
+// Use Chain() to create instances and DoChain() to execute operations.
+type RetryChain struct {
+	primary   *RetryConfig // Config consulted first for every error
+	secondary *RetryConfig // Config used for errors the primary does not handle
+}
+
+// Chain composes primary and secondary into a RetryChain. Every failed
+// attempt is routed to the first configuration whose WithRetryIf predicate
+// accepts the error and whose budget is not yet exhausted. Settings that
+// apply before an error is known, such as WithMaxAttemptDuration, are taken
+// from primary. Either configuration may be nil, in which case the chain
+// consists of the other one alone.
+//
+// Example:
+//
+//	timeouts := retry.NewRetry(
+//	    retry.WithAttempts(5),
+//	    retry.WithRetryIf(isTimeout),
+//	)
+//	rateLimits := retry.NewRetry(
+//	    retry.WithAttempts(3),
+//	    retry.WithDelay(2*time.Second),
+//	    retry.WithRetryIf(isRateLimited),
+//	)
+//	result, err := retry.DoChain(ctx, retry.Chain(timeouts, rateLimits), fn)
+func Chain(primary, secondary *RetryConfig) *RetryChain {
+	return &RetryChain{
+		primary:   primary,
+		secondary: secondary,
+	}
+}
+
+// DoChain executes fn with the retry budgets of the given chain. Each failed
+// attempt is charged to the configuration that handles the error, and the
+// delay before the next attempt is calculated by that configuration's delay
+// strategy using its own attempt counter.
+//
+// DoChain stops when fn succeeds, when an error is not handled by any
+// configuration with remaining budget, or when the context is canceled.
+// When the budgets are exhausted an *ExhaustedError is returned. The budget
+// of each configuration is resolved once per call, including
+// WithDynamicAttempts and WithUnlimitedAttempts; WithMultiError and the
+// clock are taken from the configuration that applies before an error is
+// known.
+func DoChain[T any](ctx context.Context, ch *RetryChain, fn RetryFunc[T]) (T, error) {
+	var zero T
+	lead := ch.lead()
+	budgets := ch.budgets()
+	used := map[*RetryConfig]int{}
+	var errs []error
+	start := lead.clock.Now()
+
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return zero, fmt.Errorf("context canceled before attempt %d: %w", attempt, err)
+		}
+
+		data, err := call(lead, attempt, fn)
+		if err == nil {
+			return data, nil
+		}
+
+		if isSuccess(err) {
+			return data, nil
+		}
+
+		if isIgnored(err) {
+			return zero, nil
+		}
+
+		if !isRetryable(err) || !ch.handles(err) {
+			return zero, fmt.Errorf("non-retryable error: %w", err)
+		}
+
+		errs = lead.recordError(errs, err, max(budgets[ch.primary], budgets[ch.secondary]))
+
+		rc := ch.route(err, used, budgets)
+		if rc == nil {
+			lead.logger.Printf("Retry budget for attempt %d exhausted. Last error: %v", attempt, err)
+			return zero, lead.exhaustedError(attempt, errs, lead.clock.Now().Sub(start))
+		}
+
+		// The next error may be of another class, so only give up early
+		// when no configuration has budget left at all.
+		used[rc]++
+		if !ch.remaining(used, budgets) {
+			rc.logger.Printf("All retry budgets exhausted on attempt %d. Last error: %v", attempt, err)
+			return zero, lead.exhaustedError(attempt, errs, lead.clock.Now().Sub(start))
+		}
+
+		delay := rc.nextDelay(used[rc])
+		if d, ok := retryAfterDelay(err); ok {
+			delay = d
+		}
+
+		if rc.onRetry != nil {
+			rc.lock()
+			rc.onRetry(used[rc], err, delay)
+			rc.unlock()
+		}
+
+		rc.logger.Printf("Attempt %d failed: %v. Retrying in %v...\n", attempt, err, delay)
+
+		if err := rc.sleep(ctx, delay); err != nil {
+			rc.logger.Printf("Retry canceled by context on attempt %d: %v", attempt, err)
+			return zero, fmt.Errorf("retry canceled by context on attempt %d: %w", attempt, err)
+		}
+	}
+}
+
+// lead returns the configuration whose settings apply before an error is
+// known: primary, or secondary if primary is nil. Without any configuration
+// it returns a default one, which handles no error.
+func (ch *RetryChain) lead() *RetryConfig {
+	switch {
+	case ch.primary != nil:
+		return ch.primary
+	case ch.secondary != nil:
+		return ch.secondary
+	default:
+		return NewRetry(WithRetryIf(func(error) bool { return false }))
+	}
+}
+
+// handles reports whether any configuration of the chain handles err.
+func (ch *RetryChain) handles(err error) bool {
+	return (ch.primary != nil && ch.primary.matches(err)) ||
+		(ch.secondary != nil && ch.secondary.matches(err))
+}
+
+// budgets resolves the number of attempts of every configuration of the
+// chain.
+func (ch *RetryChain) budgets() map[*RetryConfig]int {
+	budgets := map[*RetryConfig]int{}
+	for _, rc := range []*RetryConfig{ch.primary, ch.secondary} {
+		if rc != nil {
+			budgets[rc] = rc.maxAttempts()
+		}
+	}
+
+	return budgets
+}
+
+// route returns the first configuration of the chain that handles err and
+// still has budget left, or nil if there is none.
+func (ch *RetryChain) route(err error, used, budgets map[*RetryConfig]int) *RetryConfig {
+	for _, rc := range []*RetryConfig{ch.primary, ch.secondary} {
+		if rc != nil && rc.matches(err) && used[rc] < budgets[rc] {
+			return rc
+		}
+	}
+
+	return nil
+}
+
+// remaining reports whether any configuration of the chain has budget left.
+func (ch *RetryChain) remaining(used, budgets map[*RetryConfig]int) bool {
+	for rc, budget := range budgets {
+		if used[rc] < budget {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,216 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+var (
+	errChainTimeout   = errors.New("timeout")
+	errChainRateLimit = errors.New("rate limited")
+)
+
+// newChainConfig creates a fast config that only handles the given error.
+func newChainConfig(attempts int, target error) *RetryConfig {
+	return NewRetry(
+		WithAttempts(attempts),
+		WithDelay(time.Millisecond),
+		WithRetryIf(func(err error) bool { return errors.Is(err, target) }),
+	)
+}
+
+// TestDoChainRoutesErrors verifies that each error class is charged to its
+// own config and that a mix of error classes can exceed a single budget.
+func TestDoChainRoutesErrors(t *testing.T) {
+	t.Parallel()
+	var primaryRetries, secondaryRetries int
+
+	primary := newChainConfig(3, errChainTimeout)
+	primary.onRetry = func(int, error, time.Duration) { primaryRetries++ }
+	secondary := newChainConfig(3, errChainRateLimit)
+	secondary.onRetry = func(int, error, time.Duration) { secondaryRetries++ }
+
+	sequence := []error{errChainTimeout, errChainRateLimit, errChainTimeout, errChainRateLimit, nil}
+	calls := 0
+
+	result, err := DoChain(context.Background(), Chain(primary, secondary), func() (string, error) {
+		err := sequence[calls]
+		calls++
+		if err != nil {
+			return "", err
+		}
+		return "success", nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if result != "success" {
+		t.Errorf("expected 'success', got '%s'", result)
+	}
+
+	if primaryRetries != 2 || secondaryRetries != 2 {
+		t.Errorf("expected 2 retries per config, got primary=%d secondary=%d", primaryRetries, secondaryRetries)
+	}
+}
+
+// TestDoChainExhausted verifies that DoChain gives up with the last error
+// once the budget of the matching config is exhausted. As the secondary
+// config still has budget, the attempt after the last timeout retry is made.
+func TestDoChainExhausted(t *testing.T) {
+	t.Parallel()
+	primary := newChainConfig(2, errChainTimeout)
+	secondary := newChainConfig(2, errChainRateLimit)
+	calls := 0
+
+	_, err := DoChain(context.Background(), Chain(primary, secondary), func() (string, error) {
+		calls++
+		return "", errChainTimeout
+	})
+	if !errors.Is(err, errChainTimeout) {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+// TestDoChainUnhandledError verifies that an error handled by neither
+// config stops the chain immediately.
+func TestDoChainUnhandledError(t *testing.T) {
+	t.Parallel()
+	primary := newChainConfig(3, errChainTimeout)
+	secondary := newChainConfig(3, errChainRateLimit)
+	calls := 0
+
+	_, err := DoChain(context.Background(), Chain(primary, secondary), func() (string, error) {
+		calls++
+		return "", errors.New("bad request")
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+// TestDoChainNilConfig verifies that a nil config is skipped and that the
+// chain consists of the other config alone.
+func TestDoChainNilConfig(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		chain *RetryChain
+		calls int
+	}{
+		{name: "nil primary", chain: Chain(nil, newChainConfig(3, errChainTimeout)), calls: 3},
+		{name: "nil secondary", chain: Chain(newChainConfig(2, errChainTimeout), nil), calls: 2},
+		{name: "both nil", chain: Chain(nil, nil), calls: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			calls := 0
+
+			_, err := DoChain(context.Background(), tc.chain, func() (string, error) {
+				calls++
+				return "", errChainTimeout
+			})
+			if !errors.Is(err, errChainTimeout) {
+				t.Errorf("expected timeout error, got %v", err)
+			}
+
+			if calls != tc.calls {
+				t.Errorf("expected %d calls, got %d", tc.calls, calls)
+			}
+		})
+	}
+}
+
+// TestDoChainErrorClassChanges verifies that the chain continues when the
+// budget of one error class is used up but the next error is of another
+// class with budget left.
+func TestDoChainErrorClassChanges(t *testing.T) {
+	t.Parallel()
+	primary := newChainConfig(2, errChainTimeout)
+	secondary := newChainConfig(2, errChainRateLimit)
+	sequence := []error{errChainTimeout, errChainTimeout, errChainRateLimit, nil}
+	calls := 0
+
+	result, err := DoChain(context.Background(), Chain(primary, secondary), func() (string, error) {
+		err := sequence[calls]
+		calls++
+		if err != nil {
+			return "", err
+		}
+		return "success", nil
+	})
+	if err != nil || result != "success" {
+		t.Fatalf("expected success, got %q, %v", result, err)
+	}
+
+	if calls != 4 {
+		t.Errorf("expected 4 calls, got %d", calls)
+	}
+}
+
+// TestDoChainConfigOptions verifies that DoChain honors dynamic and
+// unlimited attempts and WithMultiError.
+func TestDoChainConfigOptions(t *testing.T) {
+	t.Parallel()
+	retryIf := WithRetryIf(func(err error) bool { return errors.Is(err, errChainTimeout) })
+
+	t.Run("dynamic attempts", func(t *testing.T) {
+		t.Parallel()
+		rc := NewRetry(WithDelay(time.Millisecond), WithDynamicAttempts(func() int { return 5 }), retryIf)
+		calls := 0
+		_, err := DoChain(context.Background(), Chain(rc, nil), func() (string, error) {
+			calls++
+			return "", errChainTimeout
+		})
+		if !IsExhausted(err) || calls != 5 {
+			t.Errorf("expected exhaustion after 5 calls, got %v after %d calls", err, calls)
+		}
+	})
+
+	t.Run("unlimited attempts", func(t *testing.T) {
+		t.Parallel()
+		rc := NewRetry(WithAttempts(2), WithDelay(time.Microsecond), WithUnlimitedAttempts(), retryIf)
+		calls := 0
+		_, err := DoChain(context.Background(), Chain(rc, nil), func() (string, error) {
+			calls++
+			if calls < 20 {
+				return "", errChainTimeout
+			}
+			return "success", nil
+		})
+		if err != nil || calls != 20 {
+			t.Errorf("expected success after 20 calls, got %v after %d calls", err, calls)
+		}
+	})
+
+	t.Run("multi error", func(t *testing.T) {
+		t.Parallel()
+		errFirst := fmt.Errorf("first: %w", errChainTimeout)
+		rc := NewRetry(WithAttempts(2), WithDelay(time.Millisecond), WithMultiError(), retryIf)
+		calls := 0
+		_, err := DoChain(context.Background(), Chain(rc, nil), func() (string, error) {
+			calls++
+			if calls == 1 {
+				return "", errFirst
+			}
+			return "", errChainTimeout
+		})
+		if !errors.Is(err, errFirst) {
+			t.Errorf("expected the error of the first attempt to be reachable, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package retry
+
+import "time"
+
+// Clock is the time source of a retry loop. Do reads the current time from
+// it to measure attempts and delays, and sleeps between attempts with its
+// timers, so a fake implementation lets tests advance time instantly
+// instead of waiting for real delays. Implementations must be safe for
+// concurrent use if the config is shared between goroutines.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// NewTimer creates a Timer that delivers the current time on its
+	// channel after at least d.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single-shot timer created by a Clock, like time.Timer.
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+
+	// Stop prevents the Timer from firing. It returns false if the timer
+	// has already fired or been stopped.
+	Stop() bool
+}
+
+// WithClock replaces the time source of the retry loop, e.g. with a fake
+// clock in tests. Waiting between attempts uses the timers of c and stays
+// interruptible by the context; measurements such as WithMaxElapsedTime,
+// WithFailFast, WithOffHours, the delay observer, the elapsed times of
+// ExhaustedError, DoObserve and RetrySession, and the timestamps of a Trace
+// use c.Now. DoChain uses the clock of the configuration that applies before
+// an error is known. Context deadlines, including those derived by
+// DoWithContext, still follow the real time. WithClock and WithSleepFunc
+// both replace the sleep between attempts; the one given last takes effect.
+// A nil c is ignored.
+//
+// Example:
+//
+//	// fakeClock implements retry.Clock; its timers fire immediately and
+//	// advance its time.
+//	rc := retry.NewRetry(retry.WithDelay(time.Minute), retry.WithClock(fakeClock))
+func WithClock(c Clock) Option {
+	return func(rc *RetryConfig) {
+		if c == nil {
+			return
+		}
+		rc.clock = c
+		rc.sleepFn = nil
+	}
+}
+
+// realClock is the Clock backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+// realTimer adapts time.Timer to Timer.
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
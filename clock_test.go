@@ -0,0 +1,183 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestWithClock verifies that delays and measurements use the injected
+// clock, so long delays take no real time.
+func TestWithClock(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2025, 1, 6, 12, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: start}
+
+	var actual []time.Duration
+	rc := NewRetry(
+		WithAttempts(4),
+		WithDelay(time.Hour),
+		WithClock(clock),
+		WithDelayObserver(func(_, a time.Duration) { actual = append(actual, a) }),
+	)
+
+	began := time.Now()
+	_, err := Do(context.Background(), rc, func() (int, error) {
+		return 0, errors.New("fail")
+	})
+
+	if elapsed := time.Since(began); elapsed > time.Second {
+		t.Errorf("expected no real waiting, took %v", elapsed)
+	}
+	if got := clock.Now().Sub(start); got != 3*time.Hour {
+		t.Errorf("expected the clock to advance by 3h, got %v", got)
+	}
+
+	var exhausted *ExhaustedError
+	if !errors.As(err, &exhausted) || exhausted.Elapsed() != 3*time.Hour {
+		t.Errorf("expected exhaustion after 3h, got %v", err)
+	}
+	for _, a := range actual {
+		if a != time.Hour {
+			t.Errorf("expected measured delays of 1h, got %v", actual)
+			break
+		}
+	}
+}
+
+// TestWithClockMaxElapsedTime verifies that the time budget is measured
+// with the injected clock.
+func TestWithClockMaxElapsedTime(t *testing.T) {
+	t.Parallel()
+	clock := &fakeClock{now: time.Date(2025, 1, 6, 12, 0, 0, 0, time.UTC)}
+	rc := NewRetry(
+		WithAttempts(10),
+		WithDelay(time.Minute),
+		WithMaxElapsedTime(150*time.Second),
+		WithClock(clock),
+	)
+
+	calls := 0
+	_, err := Do(context.Background(), rc, func() (int, error) {
+		calls++
+		return 0, errors.New("fail")
+	})
+
+	if !errors.Is(err, ErrMaxElapsedTimeExceeded) {
+		t.Errorf("expected ErrMaxElapsedTimeExceeded, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+// TestWithClockSleepFuncOrder verifies that of WithClock and WithSleepFunc
+// the one given last replaces the sleep.
+func TestWithClockSleepFuncOrder(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		clockLast    bool
+		expectedCall bool
+		advance      time.Duration
+	}{
+		{name: "clock last", clockLast: true, expectedCall: false, advance: time.Minute},
+		{name: "sleep func last", clockLast: false, expectedCall: true, advance: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			start := time.Date(2025, 1, 6, 12, 0, 0, 0, time.UTC)
+			clock := &fakeClock{now: start}
+			sleepCalled := false
+
+			opts := []Option{WithAttempts(2), WithDelay(time.Minute)}
+			withSleep := WithSleepFunc(func(time.Duration) { sleepCalled = true })
+			if tc.clockLast {
+				opts = append(opts, withSleep, WithClock(clock))
+			} else {
+				opts = append(opts, WithClock(clock), withSleep)
+			}
+
+			_, _ = Do(context.Background(), NewRetry(opts...), func() (int, error) {
+				return 0, errors.New("fail")
+			})
+
+			if sleepCalled != tc.expectedCall {
+				t.Errorf("expected sleep func called %v, got %v", tc.expectedCall, sleepCalled)
+			}
+			if got := clock.Now().Sub(start); got != tc.advance {
+				t.Errorf("expected the clock to advance by %v, got %v", tc.advance, got)
+			}
+		})
+	}
+}
+
+// TestWithClockElapsedTimes verifies that the elapsed times reported by the
+// entry points are measured with the injected clock.
+func TestWithClockElapsedTimes(t *testing.T) {
+	t.Parallel()
+	fail := func() (int, error) { return 0, errors.New("fail") }
+
+	testCases := []struct {
+		name    string
+		elapsed func(rc *RetryConfig) time.Duration
+	}{
+		{name: "DoObserve", elapsed: func(rc *RetryConfig) time.Duration {
+			_, meta, _ := DoObserve(context.Background(), rc, fail)
+			return meta.TotalElapsed
+		}},
+		{name: "DoWithTrace", elapsed: func(rc *RetryConfig) time.Duration {
+			tr, _, _ := DoWithTrace(context.Background(), rc, fail)
+			return tr.Duration()
+		}},
+		{name: "DoChain", elapsed: func(rc *RetryConfig) time.Duration {
+			var exhausted *ExhaustedError
+			_, err := DoChain(context.Background(), Chain(rc, nil), fail)
+			if !errors.As(err, &exhausted) {
+				return 0
+			}
+			return exhausted.Elapsed()
+		}},
+		{name: "RetrySession", elapsed: func(rc *RetryConfig) time.Duration {
+			s := NewRetrySession(rc)
+			_ = s.Do(context.Background(), func() error { return errors.New("fail") })
+			return s.Elapsed()
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			clock := &fakeClock{now: time.Date(2025, 1, 6, 12, 0, 0, 0, time.UTC)}
+			rc := NewRetry(WithAttempts(3), WithDelay(time.Hour), WithClock(clock))
+
+			if got := tc.elapsed(rc); got != 2*time.Hour {
+				t.Errorf("expected 2h elapsed, got %v", got)
+			}
+		})
+	}
+}
+
+// TestWithClockNil verifies that a nil clock is ignored and the real clock
+// stays in use.
+func TestWithClockNil(t *testing.T) {
+	t.Parallel()
+	rc := NewRetry(WithAttempts(2), WithDelay(time.Millisecond), WithClock(nil))
+
+	if _, ok := rc.clock.(realClock); !ok {
+		t.Fatalf("expected the real clock, got %T", rc.clock)
+	}
+
+	calls := 0
+	_, err := Do(context.Background(), rc, func() (int, error) {
+		calls++
+		return 0, errors.New("fail")
+	})
+	if !IsExhausted(err) || calls != 2 {
+		t.Errorf("expected exhaustion after 2 calls, got %v after %d calls", err, calls)
+	}
+}
@@ -0,0 +1,278 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// Dial connects to address on the named network like net.Dial, retrying
+// according to rc until a connection is established. Refused connections,
+// DNS failures and other transient network errors are retried as described
+// on WithNetworkErrorClassifier; other errors, such as malformed addresses,
+// stop the retry loop. ctx also cancels a dial that is in progress.
+//
+// Example:
+//
+//	conn, err := retry.Dial(ctx, "tcp", "db:5432", retry.NewRetry(
+//	    retry.WithAttempts(20),
+//	    retry.WithDelay(250*time.Millisecond),
+//	))
+func Dial(ctx context.Context, network, address string, rc *RetryConfig) (net.Conn, error) {
+	var d net.Dialer
+	return Do(ctx, rc, func() (net.Conn, error) {
+		conn, err := d.DialContext(ctx, network, address)
+		if err != nil && !isTransientNetworkError(err) {
+			return nil, NonRetryable(err)
+		}
+		return conn, err
+	})
+}
+
+// RetryConn returns a net.Conn that transparently reconnects using dialFn
+// when the underlying connection is dropped. Reads and writes that fail with
+// io.EOF or a network error close the broken connection, dial a new one and
+// are retried according to rc. Dialing is retried the same way; the first
+// connection is dialed lazily on the first read or write. ctx bounds every
+// retry loop, but not individual reads and writes; use deadlines for that.
+//
+// Deadlines are not treated as connection failures and are reapplied to
+// every new connection. Data written to a connection that dropped before the
+// peer received it may be lost, so the protocol on top should tolerate
+// reconnects, as is common for message brokers and databases.
+//
+// LocalAddr and RemoteAddr return nil while no connection is established.
+//
+// Example:
+//
+//	conn := retry.RetryConn(ctx, func() (net.Conn, error) {
+//	    return net.Dial("tcp", "broker:4222")
+//	}, retry.NewRetry(retry.WithAttempts(10)))
+//	defer conn.Close()
+func RetryConn(ctx context.Context, dialFn func() (net.Conn, error), rc *RetryConfig) net.Conn {
+	return &reconnectingConn{ctx: ctx, dial: dialFn, rc: rc}
+}
+
+// reconnectingConn implements RetryConn.
+type reconnectingConn struct {
+	ctx  context.Context
+	dial func() (net.Conn, error)
+	rc   *RetryConfig
+
+	mu            sync.Mutex
+	conn          net.Conn  // Current connection, nil until dialed
+	closed        bool      // Close was called
+	readDeadline  time.Time // Reapplied to new connections
+	writeDeadline time.Time // Reapplied to new connections
+}
+
+// Read reads from the current connection, reconnecting when it was dropped.
+func (c *reconnectingConn) Read(p []byte) (int, error) {
+	var passthrough error
+	n, err := Do(c.ctx, c.rc, func() (int, error) {
+		conn, err := c.current()
+		if errors.Is(err, net.ErrClosed) {
+			passthrough = err
+			return 0, NonRetryable(err)
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		n, err := conn.Read(p)
+		if err == nil || n > 0 {
+			return n, nil
+		}
+		if !reconnectable(err) {
+			passthrough = err
+			return 0, NonRetryable(err)
+		}
+
+		c.drop(conn)
+		return 0, err
+	})
+	if passthrough != nil {
+		return 0, passthrough
+	}
+
+	return n, err
+}
+
+// Write writes to the current connection, reconnecting when it was dropped
+// and continuing with the bytes that were not written yet.
+func (c *reconnectingConn) Write(p []byte) (int, error) {
+	var passthrough error
+	written := 0
+	_, err := Do(c.ctx, c.rc, func() (struct{}, error) {
+		conn, err := c.current()
+		if errors.Is(err, net.ErrClosed) {
+			passthrough = err
+			return struct{}{}, NonRetryable(err)
+		}
+		if err != nil {
+			return struct{}{}, err
+		}
+
+		n, err := conn.Write(p[written:])
+		written += n
+		if err == nil {
+			return struct{}{}, nil
+		}
+		if !reconnectable(err) {
+			passthrough = err
+			return struct{}{}, NonRetryable(err)
+		}
+
+		c.drop(conn)
+		return struct{}{}, err
+	})
+	if passthrough != nil {
+		return written, passthrough
+	}
+
+	return written, err
+}
+
+// Close closes the current connection. Later reads and writes fail with
+// net.ErrClosed.
+func (c *reconnectingConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	if c.conn == nil {
+		return nil
+	}
+
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// LocalAddr returns the local address of the current connection.
+func (c *reconnectingConn) LocalAddr() net.Addr {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.LocalAddr()
+}
+
+// RemoteAddr returns the remote address of the current connection.
+func (c *reconnectingConn) RemoteAddr() net.Addr {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.RemoteAddr()
+}
+
+// SetDeadline sets the read and write deadlines of the current and all
+// future connections.
+func (c *reconnectingConn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.SetWriteDeadline(t)
+}
+
+// SetReadDeadline sets the read deadline of the current and all future
+// connections.
+func (c *reconnectingConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.readDeadline = t
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets the write deadline of the current and all future
+// connections.
+func (c *reconnectingConn) SetWriteDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.writeDeadline = t
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.SetWriteDeadline(t)
+}
+
+// current returns the current connection, dialing a new one if there is
+// none. The lock is not held while dialing, so Close and the deadline
+// setters do not wait for a slow dial.
+func (c *reconnectingConn) current() (net.Conn, error) {
+	c.mu.Lock()
+	closed, conn := c.closed, c.conn
+	c.mu.Unlock()
+
+	if closed {
+		return nil, net.ErrClosed
+	}
+	if conn != nil {
+		return conn, nil
+	}
+
+	conn, err := c.dial()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Close may have been called, or another caller may have installed a
+	// connection, while dialing.
+	if c.closed {
+		_ = conn.Close()
+		return nil, net.ErrClosed
+	}
+	if c.conn != nil {
+		_ = conn.Close()
+		return c.conn, nil
+	}
+
+	_ = conn.SetReadDeadline(c.readDeadline)
+	_ = conn.SetWriteDeadline(c.writeDeadline)
+	c.conn = conn
+
+	return conn, nil
+}
+
+// drop closes conn if it is still the current connection, so that the next
+// call to current dials a new one. Concurrent reads and writes failing on
+// the same connection therefore trigger a single reconnect.
+func (c *reconnectingConn) drop(conn net.Conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == conn {
+		_ = conn.Close()
+		c.conn = nil
+	}
+}
+
+// reconnectable reports whether err indicates a dropped connection.
+func reconnectable(err error) bool {
+	if errors.Is(err, io.EOF) {
+		return true
+	}
+	if errors.Is(err, os.ErrDeadlineExceeded) || errors.Is(err, net.ErrClosed) {
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
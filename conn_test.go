@@ -0,0 +1,229 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestRetryConnReconnectsOnEOF verifies that a read on a connection closed by
+// the server transparently continues on a new connection.
+func TestRetryConnReconnectsOnEOF(t *testing.T) {
+	t.Parallel()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	go func() {
+		// The first connection is dropped immediately, the second
+		// one delivers the payload.
+		first, err := l.Accept()
+		if err != nil {
+			return
+		}
+		first.Close()
+
+		second, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer second.Close()
+		_, _ = second.Write([]byte("hello"))
+	}()
+
+	var dials atomic.Int32
+	conn := RetryConn(context.Background(), func() (net.Conn, error) {
+		dials.Add(1)
+		return net.Dial("tcp", l.Addr().String())
+	}, NewRetry(WithAttempts(3), WithDelay(time.Millisecond)))
+	defer conn.Close()
+
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		t.Fatalf("expected read to succeed, got %v", err)
+	}
+	if string(buf) != "hello" {
+		t.Errorf("expected %q, got %q", "hello", buf)
+	}
+	if got := dials.Load(); got != 2 {
+		t.Errorf("expected 2 dials, got %d", got)
+	}
+}
+
+// brokenConn is a net.Conn whose writes fail with a broken pipe after the
+// given number of bytes.
+type brokenConn struct {
+	net.Conn
+	limit   int
+	written []byte
+}
+
+func (c *brokenConn) Write(p []byte) (int, error) {
+	n := min(len(p), c.limit)
+	c.written = append(c.written, p[:n]...)
+	if n < len(p) {
+		return n, &net.OpError{Op: "write", Net: "tcp", Err: syscall.EPIPE}
+	}
+	return n, nil
+}
+
+func (c *brokenConn) Close() error                     { return nil }
+func (c *brokenConn) SetReadDeadline(time.Time) error  { return nil }
+func (c *brokenConn) SetWriteDeadline(time.Time) error { return nil }
+
+// TestRetryConnWriteResumes verifies that a partial write continues with
+// the remaining bytes on a new connection.
+func TestRetryConnWriteResumes(t *testing.T) {
+	t.Parallel()
+	conns := []*brokenConn{{limit: 3}, {limit: 100}}
+	dials := 0
+	conn := RetryConn(context.Background(), func() (net.Conn, error) {
+		c := conns[dials]
+		dials++
+		return c, nil
+	}, NewRetry(WithAttempts(3), WithDelay(time.Millisecond)))
+
+	n, err := conn.Write([]byte("abcdef"))
+	if err != nil || n != 6 {
+		t.Fatalf("expected 6 bytes written without error, got %d, %v", n, err)
+	}
+	if string(conns[0].written) != "abc" || string(conns[1].written) != "def" {
+		t.Errorf("expected writes %q and %q, got %q and %q", "abc", "def", conns[0].written, conns[1].written)
+	}
+}
+
+// TestRetryConnErrors verifies that deadlines and closed connections are
+// reported unchanged and that failing dials exhaust the retry budget.
+func TestRetryConnErrors(t *testing.T) {
+	t.Parallel()
+	rc := NewRetry(WithAttempts(2), WithDelay(time.Millisecond))
+
+	client, server := net.Pipe()
+	defer server.Close()
+	conn := RetryConn(context.Background(), func() (net.Conn, error) { return client, nil }, rc)
+	if err := conn.SetReadDeadline(time.Now().Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Read(make([]byte, 1)); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("expected os.ErrDeadlineExceeded, got %v", err)
+	}
+
+	conn.Close()
+	if _, err := conn.Write([]byte("x")); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("expected net.ErrClosed after Close, got %v", err)
+	}
+
+	failing := RetryConn(context.Background(), func() (net.Conn, error) {
+		return nil, errors.New("connection refused")
+	}, rc)
+	if _, err := failing.Read(make([]byte, 1)); !errors.Is(err, ErrExhausted) {
+		t.Errorf("expected ErrExhausted, got %v", err)
+	}
+}
+
+// closeRecordingConn is a net.Conn that records whether it was closed.
+type closeRecordingConn struct {
+	net.Conn
+	closed atomic.Bool
+}
+
+func (c *closeRecordingConn) Close() error {
+	c.closed.Store(true)
+	return nil
+}
+
+// TestRetryConnCloseDuringDial verifies that Close does not wait for a dial
+// in progress and that the connection dialed meanwhile is closed.
+func TestRetryConnCloseDuringDial(t *testing.T) {
+	t.Parallel()
+	dialing, release := make(chan struct{}), make(chan struct{})
+	dialed := &closeRecordingConn{}
+	conn := RetryConn(context.Background(), func() (net.Conn, error) {
+		close(dialing)
+		<-release
+		return dialed, nil
+	}, NewRetry(WithAttempts(1)))
+
+	readErr := make(chan error, 1)
+	go func() {
+		_, err := conn.Read(make([]byte, 1))
+		readErr <- err
+	}()
+
+	<-dialing
+	closeDone := make(chan struct{})
+	go func() {
+		conn.Close()
+		close(closeDone)
+	}()
+	select {
+	case <-closeDone:
+	case <-time.After(time.Second):
+		t.Fatal("expected Close not to wait for the dial")
+	}
+
+	close(release)
+	if err := <-readErr; !errors.Is(err, net.ErrClosed) {
+		t.Errorf("expected net.ErrClosed, got %v", err)
+	}
+	if !dialed.closed.Load() {
+		t.Error("expected the connection dialed during Close to be closed")
+	}
+}
+
+// TestDialListenerStartsLate verifies that Dial retries refused connections
+// until a listener is started.
+func TestDialListenerStartsLate(t *testing.T) {
+	t.Parallel()
+	// Reserve a free port, then release it so that dialing is refused
+	// until the listener below is started.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer l.Close()
+		if conn, err := l.Accept(); err == nil {
+			conn.Close()
+		}
+	}()
+
+	conn, err := Dial(context.Background(), "tcp", addr, NewRetry(WithAttempts(100), WithDelay(10*time.Millisecond)))
+	if err != nil {
+		t.Fatalf("expected connection, got %v", err)
+	}
+	conn.Close()
+}
+
+// TestDialInvalidAddress verifies that malformed addresses are not retried.
+func TestDialInvalidAddress(t *testing.T) {
+	t.Parallel()
+	retries := 0
+	rc := NewRetry(WithAttempts(5), WithDelay(time.Millisecond), WithOnRetry(func(int, error, time.Duration) {
+		retries++
+	}))
+
+	if _, err := Dial(context.Background(), "tcp", "127.0.0.1:99999", rc); err == nil || isRetryable(err) {
+		t.Errorf("expected non-retryable error, got %v", err)
+	}
+	if retries != 0 {
+		t.Errorf("expected no retries, got %d", retries)
+	}
+}
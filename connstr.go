@@ -0,0 +1,121 @@
+package retry
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// connStrPrefix marks query parameters that carry retry settings.
+const connStrPrefix = "retry_"
+
+// NewRetryFromConnStr creates a RetryConfig from retry hints embedded in the
+// query of a connection string and returns the connection string without
+// them. The following parameters are recognized; missing ones keep the
+// defaults of NewRetry:
+//   - retry_attempts: number of attempts, e.g. 5
+//   - retry_base_delay: base delay, e.g. 100ms
+//   - retry_max_delay: maximum delay, e.g. 5s
+//   - retry_strategy: "fixed" or "exponential"
+//
+// Both URL-style DSNs, as used by PostgreSQL, and the MySQL format
+// "user:pass@tcp(host:3306)/db?...", with or without a password, are
+// supported. Only the query is parsed, so the rest of the connection string
+// is returned unchanged and the order of the other parameters is preserved.
+// The opts are applied after the parsed settings and the result is
+// validated. Errors wrap ErrInvalidConfig.
+//
+// Example:
+//
+//	rc, dsn, err := retry.NewRetryFromConnStr(
+//	    "postgres://app@db/orders?sslmode=require&retry_attempts=5&retry_base_delay=100ms",
+//	)
+//	// dsn == "postgres://app@db/orders?sslmode=require"
+func NewRetryFromConnStr(connStr string, opts ...Option) (*RetryConfig, string, error) {
+	base, query := splitConnStr(connStr)
+
+	b := NewRetryBuilder()
+	var kept []string
+	for _, param := range strings.Split(query, "&") {
+		rawKey, rawValue, _ := strings.Cut(param, "=")
+		if !strings.HasPrefix(rawKey, connStrPrefix) {
+			if param != "" {
+				kept = append(kept, param)
+			}
+			continue
+		}
+
+		value, err := url.QueryUnescape(rawValue)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: %s: %w", ErrInvalidConfig, rawKey, err)
+		}
+		if err := applyConnStrParam(b, strings.TrimPrefix(rawKey, connStrPrefix), value); err != nil {
+			return nil, "", fmt.Errorf("%w: %s: %w", ErrInvalidConfig, rawKey, err)
+		}
+	}
+
+	rc, err := b.With(opts...).Build()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(kept) == 0 {
+		return rc, base, nil
+	}
+	return rc, base + "?" + strings.Join(kept, "&"), nil
+}
+
+// splitConnStr splits a connection string at the start of its query,
+// following the rules of the respective format: in URL-style DSNs the
+// userinfo and path cannot contain an unescaped '?', while MySQL DSNs may
+// have one in the password, so their query starts after the last '/'.
+func splitConnStr(connStr string) (base, query string) {
+	offset := 0
+	if !strings.Contains(connStr, "://") {
+		offset = strings.LastIndex(connStr, "/") + 1
+	}
+
+	i := strings.Index(connStr[offset:], "?")
+	if i < 0 {
+		return connStr, ""
+	}
+	return connStr[:offset+i], connStr[offset+i+1:]
+}
+
+// applyConnStrParam records the option for a single retry parameter,
+// given without its prefix.
+func applyConnStrParam(b *RetryBuilder, name, value string) error {
+	switch name {
+	case "attempts":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		b.Attempts(n)
+	case "base_delay", "max_delay":
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		if name == "base_delay" {
+			b.Delay(d)
+		} else {
+			b.MaxDelay(d)
+		}
+	case "strategy":
+		switch value {
+		case "fixed":
+			b.DelayType(FixedDelay())
+		case "exponential":
+			b.DelayType(ExpBackoffWithJitter())
+		default:
+			return fmt.Errorf("unknown strategy %q", value)
+		}
+	default:
+		return fmt.Errorf("unknown retry parameter")
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestNewRetryFromConnStr verifies that retry hints are parsed and stripped
+// from PostgreSQL and MySQL connection strings.
+func TestNewRetryFromConnStr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name        string
+		connStr     string
+		expectedRC  string
+		expectedDSN string
+	}{
+		{
+			name:        "postgres",
+			connStr:     "postgres://app:secret@db:5432/orders?sslmode=require&retry_attempts=5&retry_base_delay=100ms&application_name=api",
+			expectedRC:  "attempts=5 baseDelay=100ms maxDelay=1s strategy=FixedDelay onRetry=false",
+			expectedDSN: "postgres://app:secret@db:5432/orders?sslmode=require&application_name=api",
+		},
+		{
+			name:        "mysql",
+			connStr:     "app:secret@tcp(db:3306)/orders?parseTime=true&retry_max_delay=5s&retry_strategy=exponential",
+			expectedRC:  "attempts=3 baseDelay=100ms maxDelay=5s strategy=ExpBackoffWithJitter onRetry=false",
+			expectedDSN: "app:secret@tcp(db:3306)/orders?parseTime=true",
+		},
+		{
+			name:        "mysql without password",
+			connStr:     "app@tcp(db:3306)/orders?retry_attempts=4&parseTime=true",
+			expectedRC:  "attempts=4 baseDelay=100ms maxDelay=1s strategy=FixedDelay onRetry=false",
+			expectedDSN: "app@tcp(db:3306)/orders?parseTime=true",
+		},
+		{
+			name:        "mysql with question mark in password",
+			connStr:     "app:se?cret@tcp(db:3306)/orders?retry_attempts=2",
+			expectedRC:  "attempts=2 baseDelay=100ms maxDelay=1s strategy=FixedDelay onRetry=false",
+			expectedDSN: "app:se?cret@tcp(db:3306)/orders",
+		},
+		{
+			name:        "only retry parameters",
+			connStr:     "postgres://db/orders?retry_attempts=2",
+			expectedRC:  "attempts=2 baseDelay=100ms maxDelay=1s strategy=FixedDelay onRetry=false",
+			expectedDSN: "postgres://db/orders",
+		},
+		{
+			name:        "no parameters",
+			connStr:     "postgres://db/orders",
+			expectedRC:  "attempts=3 baseDelay=100ms maxDelay=1s strategy=FixedDelay onRetry=false",
+			expectedDSN: "postgres://db/orders",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rc, dsn, err := NewRetryFromConnStr(tc.connStr)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if got := rc.String(); got != tc.expectedRC {
+				t.Errorf("expected config %q, got %q", tc.expectedRC, got)
+			}
+			if dsn != tc.expectedDSN {
+				t.Errorf("expected DSN %q, got %q", tc.expectedDSN, dsn)
+			}
+		})
+	}
+}
+
+// TestNewRetryFromConnStrInvalid verifies that malformed retry hints are
+// rejected with ErrInvalidConfig.
+func TestNewRetryFromConnStrInvalid(t *testing.T) {
+	t.Parallel()
+	testCases := []string{
+		"postgres://db/orders?retry_attempts=many",
+		"postgres://db/orders?retry_attempts=0",
+		"postgres://db/orders?retry_base_delay=soon",
+		"postgres://db/orders?retry_strategy=linear",
+		"postgres://db/orders?retry_unknown=1",
+		"postgres://db/orders?retry_attempts=%zz",
+	}
+
+	for _, connStr := range testCases {
+		t.Run(connStr, func(t *testing.T) {
+			t.Parallel()
+			if _, _, err := NewRetryFromConnStr(connStr); !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("expected ErrInvalidConfig, got %v", err)
+			}
+		})
+	}
+}
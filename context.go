@@ -0,0 +1,87 @@
+package retry
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// RetryFuncWithContext defines the signature for retryable operations that
+// receive the context of the current attempt.
+type RetryFuncWithContext[T any] func(ctx context.Context) (T, error)
+
+// DoWithContext executes fn like Do, but passes a context to every attempt.
+// Without WithContextValues and WithContextTagger each attempt receives ctx
+// itself; otherwise it receives ctx with the configured values added and
+// passed through the tagger for that attempt. With WithMaxAttemptDuration
+// the context of each attempt also carries that timeout, so operations that
+// honor it stop when their attempt is abandoned.
+//
+// Example:
+//
+//	rc := retry.NewRetry(retry.WithContextTagger(func(ctx context.Context, attempt int) context.Context {
+//	    return context.WithValue(ctx, attemptKey{}, attempt)
+//	}))
+//	resp, err := retry.DoWithContext(ctx, rc, func(ctx context.Context) (*http.Response, error) {
+//	    req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+//	    return http.DefaultClient.Do(req)
+//	})
+func DoWithContext[T any](ctx context.Context, rc *RetryConfig, fn RetryFuncWithContext[T]) (T, error) {
+	tracker := &attemptTracker{}
+
+	return do(ctx, rc, func() (T, error) {
+		attemptCtx := ctx
+		for key, val := range rc.contextValues {
+			attemptCtx = context.WithValue(attemptCtx, key, val)
+		}
+		if rc.contextTagger != nil {
+			attemptCtx = rc.contextTagger(attemptCtx, tracker.current())
+		}
+		if rc.maxAttemptDuration > 0 {
+			var cancel context.CancelFunc
+			attemptCtx, cancel = context.WithTimeout(attemptCtx, rc.maxAttemptDuration)
+			defer cancel()
+		}
+
+		return fn(attemptCtx)
+	}, loopState{observer: tracker})
+}
+
+// attemptTracker is an observer that remembers the number of the attempt
+// that is currently running. The attempt is stored atomically because with
+// WithMaxAttemptDuration the operation reads it in its own goroutine.
+type attemptTracker struct {
+	attempt atomic.Int64
+}
+
+func (a *attemptTracker) record(kind TraceEventKind, attempt int, _ error, _ time.Duration) {
+	if kind == EventAttemptStart {
+		a.attempt.Store(int64(attempt))
+	}
+}
+
+// current returns the number of the running attempt.
+func (a *attemptTracker) current() int {
+	return int(a.attempt.Load())
+}
+
+// WrapContext returns a function with the signature of fn that runs fn
+// with the retry policy rc. The context passed to the returned function
+// bounds the retry loop and is handed to every attempt of fn, including the
+// values of WithContextValues and WithContextTagger, so cancellation stops
+// both the delays and a running attempt that honors it.
+//
+// Example:
+//
+//	sync := retry.WrapContext(rc, client.Sync)
+//	if err := sync(ctx); err != nil {
+//	    return err
+//	}
+func WrapContext(rc *RetryConfig, fn func(context.Context) error) func(context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := DoWithContext(ctx, rc, func(ctx context.Context) (struct{}, error) {
+			return struct{}{}, fn(ctx)
+		})
+		return err
+	}
+}
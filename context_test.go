@@ -0,0 +1,219 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type attemptKey struct{}
+
+// TestDoWithContextTagger verifies that every attempt receives the context
+// produced by the tagger and that the tagger always gets the original context.
+func TestDoWithContextTagger(t *testing.T) {
+	t.Parallel()
+	var taggerSaw []any
+	rc := NewRetry(
+		WithAttempts(3),
+		WithDelay(time.Millisecond),
+		WithContextTagger(func(ctx context.Context, attempt int) context.Context {
+			taggerSaw = append(taggerSaw, ctx.Value(attemptKey{}))
+			return context.WithValue(ctx, attemptKey{}, attempt)
+		}),
+	)
+
+	var fnSaw []any
+	_, err := DoWithContext(context.Background(), rc, func(ctx context.Context) (int, error) {
+		fnSaw = append(fnSaw, ctx.Value(attemptKey{}))
+		return 0, errors.New("fail")
+	})
+	if !errors.Is(err, ErrExhausted) {
+		t.Fatalf("expected ErrExhausted, got %v", err)
+	}
+
+	if !slices.Equal(fnSaw, []any{1, 2, 3}) {
+		t.Errorf("expected attempts [1 2 3] in the attempt contexts, got %v", fnSaw)
+	}
+	if !slices.Equal(taggerSaw, []any{nil, nil, nil}) {
+		t.Errorf("expected the tagger to always receive the original context, got %v", taggerSaw)
+	}
+}
+
+// TestDoWithContextWithoutTagger verifies that the original context is passed
+// through when no tagger is configured.
+func TestDoWithContextWithoutTagger(t *testing.T) {
+	t.Parallel()
+	ctx := context.WithValue(context.Background(), attemptKey{}, "original")
+
+	result, err := DoWithContext(ctx, NewRetry(), func(ctx context.Context) (any, error) {
+		return ctx.Value(attemptKey{}), nil
+	})
+	if err != nil || result != "original" {
+		t.Errorf("expected the original context, got %v, %v", result, err)
+	}
+}
+
+// TestDoWithContextMaxAttemptDuration verifies that each attempt receives
+// a context that is canceled when the attempt times out, while the context
+// of the retry loop stays usable.
+func TestDoWithContextMaxAttemptDuration(t *testing.T) {
+	t.Parallel()
+	rc := NewRetry(WithAttempts(2), WithDelay(time.Millisecond), WithMaxAttemptDuration(20*time.Millisecond))
+
+	var calls atomic.Int32
+	result, err := DoWithContext(context.Background(), rc, func(ctx context.Context) (string, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			return "", NonRetryable(errors.New("expected an attempt deadline"))
+		}
+		if calls.Add(1) == 1 {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+		return "done", nil
+	})
+
+	if err != nil || result != "done" {
+		t.Errorf("expected success on the second attempt, got %q, %v", result, err)
+	}
+}
+
+type traceIDKey struct{}
+
+type userIDKey struct{}
+
+// TestDoWithContextValues verifies that all configured values are available
+// in the context of every attempt, including the one the tagger receives.
+func TestDoWithContextValues(t *testing.T) {
+	t.Parallel()
+	vals := map[any]any{traceIDKey{}: "trace-1", userIDKey{}: 42}
+	var taggerSaw []any
+	rc := NewRetry(
+		WithAttempts(2),
+		WithDelay(time.Millisecond),
+		WithContextValues(vals),
+		WithContextTagger(func(ctx context.Context, attempt int) context.Context {
+			taggerSaw = append(taggerSaw, ctx.Value(traceIDKey{}))
+			return context.WithValue(ctx, attemptKey{}, attempt)
+		}),
+	)
+	vals[traceIDKey{}] = "changed"
+
+	var fnSaw [][]any
+	_, err := DoWithContext(context.Background(), rc, func(ctx context.Context) (int, error) {
+		fnSaw = append(fnSaw, []any{ctx.Value(traceIDKey{}), ctx.Value(userIDKey{}), ctx.Value(attemptKey{})})
+		return 0, errors.New("fail")
+	})
+	if !errors.Is(err, ErrExhausted) {
+		t.Fatalf("expected ErrExhausted, got %v", err)
+	}
+
+	for i, saw := range fnSaw {
+		if !slices.Equal(saw, []any{"trace-1", 42, i + 1}) {
+			t.Errorf("attempt %d: expected [trace-1 42 %d], got %v", i+1, i+1, saw)
+		}
+	}
+	if !slices.Equal(taggerSaw, []any{"trace-1", "trace-1"}) {
+		t.Errorf("expected the tagger to see the values, got %v", taggerSaw)
+	}
+}
+
+// TestWrapContext verifies that the wrapped function retries and that the
+// caller's context reaches the inner function.
+func TestWrapContext(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	wrapped := WrapContext(NewRetry(WithAttempts(3), WithDelay(time.Millisecond)), func(ctx context.Context) error {
+		calls++
+		if ctx.Value(attemptKey{}) != "caller" {
+			return NonRetryable(errors.New("caller context not propagated"))
+		}
+		if calls < 3 {
+			return errors.New("fail")
+		}
+		return nil
+	})
+
+	if err := wrapped(context.WithValue(context.Background(), attemptKey{}, "caller")); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+// TestWrapContextCancellation verifies that canceling the caller's context
+// interrupts a running attempt and stops the retry loop.
+func TestWrapContextCancellation(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	wrapped := WrapContext(NewRetry(WithAttempts(5), WithDelay(time.Hour)), func(ctx context.Context) error {
+		calls++
+		cancel()
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- wrapped(ctx) }()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the retry loop to stop after cancellation")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+type noRetryKey struct{}
+
+// TestDoAbortOnContextValues verifies that a truthy context value stops
+// retrying after the first attempt while falsy values are ignored.
+func TestDoAbortOnContextValues(t *testing.T) {
+	t.Parallel()
+	errFail := errors.New("fail")
+
+	testCases := []struct {
+		name          string
+		value         any
+		expectedCalls int
+	}{
+		{name: "true", value: true, expectedCalls: 1},
+		{name: "non-empty string", value: "reason", expectedCalls: 1},
+		{name: "false", value: false, expectedCalls: 3},
+		{name: "unset", value: nil, expectedCalls: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rc := NewRetry(WithAttempts(3), WithDelay(time.Millisecond), WithAbortOnContextValues(attemptKey{}, noRetryKey{}))
+
+			ctx := context.Background()
+			if tc.value != nil {
+				ctx = context.WithValue(ctx, noRetryKey{}, tc.value)
+			}
+
+			calls := 0
+			_, err := Do(ctx, rc, func() (int, error) {
+				calls++
+				return 0, errFail
+			})
+
+			if !errors.Is(err, errFail) {
+				t.Errorf("expected the last attempt error, got %v", err)
+			}
+			if calls != tc.expectedCalls {
+				t.Errorf("expected %d calls, got %d", tc.expectedCalls, calls)
+			}
+		})
+	}
+}
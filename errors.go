@@ -4,12 +4,65 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"time"
 )
 
 // errNonRetryable is a sentinel error used to mark operations that should not
 // be retried. This prevents infinite retry loops for critical failures.
 var errNonRetryable = errors.New("non-retryable error")
 
+// ErrInvalidConfig is returned when a retry configuration fails validation,
+// for example by RetryBuilder.Build.
+var ErrInvalidConfig = errors.New("invalid retry config")
+
+// ErrExhausted is reported by errors.Is for errors returned when all retry
+// attempts have failed, by Do and every variant built on it, including
+// DoChain. Errors that stop the retry loop early, such as non-retryable
+// errors or a canceled context, do not match it, so callers can tell giving
+// up after the configured attempts from other failures without inspecting
+// the message.
+//
+// Example:
+//
+//	if errors.Is(err, retry.ErrExhausted) {
+//	    metrics.IncGaveUp()
+//	}
+var ErrExhausted = errors.New("all attempts failed")
+
+// ErrNoAttempts is the last error of an *ExhaustedError returned when the
+// budget did not allow a single attempt, e.g. with WithAttempts(0), which
+// NewRetry accepts but RetryBuilder.Build rejects, or when
+// WithDynamicAttempts returns 0. The operation is not called.
+var ErrNoAttempts = errors.New("no attempt was made")
+
+// ErrAttemptTimedOut is returned for an attempt that did not finish within
+// the duration configured with WithMaxAttemptDuration. It is retryable.
+var ErrAttemptTimedOut = errors.New("attempt timed out")
+
+// ErrPanicked is returned for an attempt that panicked while
+// WithRecoverPanics is enabled. It is retryable.
+var ErrPanicked = errors.New("attempt panicked")
+
+// ErrCostBudgetExceeded is returned when the next attempt would exceed the
+// budget set with WithTotalCostBudget.
+var ErrCostBudgetExceeded = errors.New("cost budget exceeded")
+
+// ErrMaxElapsedTimeExceeded is returned when the next delay would extend
+// the retry loop beyond the duration set with WithMaxElapsedTime.
+var ErrMaxElapsedTimeExceeded = errors.New("max elapsed time exceeded")
+
+// errIgnored is a sentinel error used to mark errors that should be ignored
+// entirely. Do treats an ignored error as a successful completion.
+var errIgnored = errors.New("ignored error")
+
+// errSuccess is a sentinel error used to mark errors that represent the
+// expected terminal condition of an operation, such as io.EOF.
+var errSuccess = errors.New("success on error")
+
+// errSoftRetry is a sentinel error used to mark failures that are retried
+// without consuming the retry budget.
+var errSoftRetry = errors.New("soft retry")
+
 // NonRetryable wraps an error to explicitly mark it as non-retryable.
 // Use this function to prevent retry attempts for critical errors like
 // authentication failures, invalid input, or configuration errors.
@@ -23,6 +76,218 @@ func NonRetryable(err error) error {
 	return fmt.Errorf("%w: %v", errNonRetryable, err)
 }
 
+// IgnoreError wraps an error to mark it as ignorable. When the retry function
+// returns an ignorable error, Do stops retrying and reports success by
+// returning the zero value of T and a nil error.
+//
+// This is useful for idempotent operations where a particular failure
+// actually means the desired state has already been reached.
+//
+// Example:
+//
+//	if errors.Is(err, ErrKeyExists) {
+//	    return nil, retry.IgnoreError(err)
+//	}
+func IgnoreError(err error) error {
+	return fmt.Errorf("%w: %w", errIgnored, err)
+}
+
+// isIgnored reports whether an error was marked with IgnoreError().
+func isIgnored(err error) bool {
+	return errors.Is(err, errIgnored)
+}
+
+// SuccessOnError wraps an error to mark it as the expected terminal condition
+// of an operation. When the retry function returns such an error, Do stops
+// retrying and returns the value produced alongside it with a nil error.
+//
+// Unlike IgnoreError, which discards the result, SuccessOnError keeps the
+// partial result returned by the final attempt.
+//
+// Example:
+//
+//	n, err := r.Read(buf)
+//	if errors.Is(err, io.EOF) {
+//	    return n, retry.SuccessOnError(err)
+//	}
+func SuccessOnError(err error) error {
+	return fmt.Errorf("%w: %w", errSuccess, err)
+}
+
+// isSuccess reports whether an error was marked with SuccessOnError().
+func isSuccess(err error) bool {
+	return errors.Is(err, errSuccess)
+}
+
+// SoftRetry wraps an error to mark it as a soft retry. Do retries soft
+// failures after the regular delay, but they do not count against the
+// configured number of attempts: the attempt number passed to the delay
+// strategy and hooks stays the same. Regular failures still consume the
+// budget as usual.
+//
+// Use this for expected, harmless failures, e.g. a background health check
+// that keeps a connection alive while waiting, without using up the budget
+// reserved for real errors. As soft retries never exhaust the budget, make
+// sure they stop eventually or bound the loop with the context.
+//
+// Example:
+//
+//	if errors.Is(err, ErrNotReadyYet) {
+//	    return nil, retry.SoftRetry(err)
+//	}
+func SoftRetry(err error) error {
+	return fmt.Errorf("%w: %w", errSoftRetry, err)
+}
+
+// isSoftRetry reports whether an error was marked with SoftRetry().
+func isSoftRetry(err error) bool {
+	return errors.Is(err, errSoftRetry)
+}
+
+// retryAfterError carries a delay requested by the failing operation, for
+// example from an HTTP Retry-After header.
+type retryAfterError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *retryAfterError) Error() string { return e.err.Error() }
+func (e *retryAfterError) Unwrap() error { return e.err }
+
+// RetryAfter wraps an error together with the delay the operation asks to
+// wait before the next attempt. Do uses this delay instead of the one
+// calculated by the delay strategy. The error stays retryable.
+//
+// Example:
+//
+//	if resp.StatusCode == http.StatusTooManyRequests {
+//	    return nil, retry.RetryAfter(errRateLimited, 5*time.Second)
+//	}
+func RetryAfter(err error, delay time.Duration) error {
+	return &retryAfterError{err: err, delay: delay}
+}
+
+// retryAfterDelay extracts the delay requested with RetryAfter(), if any.
+func retryAfterDelay(err error) (time.Duration, bool) {
+	var raErr *retryAfterError
+	if errors.As(err, &raErr) {
+		return raErr.delay, true
+	}
+
+	return 0, false
+}
+
+// ExhaustedError is returned by Do when all attempts have failed. It keeps
+// the errors of all attempts, returned by AllErrors and AllAttemptErrors,
+// and the time the retry loop took. errors.Is(err, ErrExhausted) reports
+// true.
+//
+// By default errors.Is and errors.As inspect only the error of the last
+// attempt, so that an error an earlier attempt recovered from does not
+// decide how the caller handles the failure. With WithMultiError they
+// inspect the errors of all attempts, as if joined with errors.Join.
+type ExhaustedError struct {
+	attempts int           // Number of calls of the operation
+	errs     []error       // Errors of all failed attempts, in order
+	multi    bool          // Unwrap to all errors instead of the last one
+	elapsed  time.Duration // Wall time of the retry loop, including delays
+}
+
+// Error implements the error interface.
+func (e *ExhaustedError) Error() string {
+	if e.multi {
+		return fmt.Sprintf("all attempts failed, the errors:\n%v", errors.Join(e.errs...))
+	}
+
+	return fmt.Sprintf("all attempts failed, the last error: %v", e.last())
+}
+
+// Unwrap returns the error of the last attempt, or the errors of all
+// attempts joined with errors.Join if WithMultiError is set. Without
+// WithMultiError the earlier errors are available from AllErrors only.
+func (e *ExhaustedError) Unwrap() error {
+	if e.multi {
+		return errors.Join(e.errs...)
+	}
+
+	return e.last()
+}
+
+// Is reports whether target is ErrExhausted.
+func (e *ExhaustedError) Is(target error) bool {
+	return target == ErrExhausted
+}
+
+// IsExhausted reports true; it allows checking an error for exhaustion via
+// an interface without importing this package.
+func (e *ExhaustedError) IsExhausted() bool {
+	return true
+}
+
+// TotalAttempts returns the number of attempts that were made, i.e. the
+// number of calls of the operation, which may be lower than the configured
+// number of attempts. Attempts skipped by WithAttemptFilter are not
+// counted; soft retries are. For a resumed RetrySession it includes the
+// attempts made before the restart.
+func (e *ExhaustedError) TotalAttempts() int {
+	return e.attempts
+}
+
+// Elapsed returns the wall time of the retry loop, including the attempts
+// and the delays between them.
+func (e *ExhaustedError) Elapsed() time.Duration {
+	return e.elapsed
+}
+
+// AllErrors returns a copy of the errors of all failed attempts, in order.
+func (e *ExhaustedError) AllErrors() []error {
+	return append([]error(nil), e.errs...)
+}
+
+// AllAttemptErrors returns the errors of all failed attempts recorded in
+// err, in order. It finds an *ExhaustedError anywhere in the chain of err;
+// otherwise, if err was created with errors.Join, it returns the joined
+// errors. For any other error it returns nil.
+//
+// Example:
+//
+//	for i, attemptErr := range retry.AllAttemptErrors(err) {
+//	    log.Printf("attempt %d: %v", i+1, attemptErr)
+//	}
+func AllAttemptErrors(err error) []error {
+	var exhausted *ExhaustedError
+	if errors.As(err, &exhausted) {
+		return exhausted.AllErrors()
+	}
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return append([]error(nil), joined.Unwrap()...)
+	}
+
+	return nil
+}
+
+// last returns the error of the last attempt, or nil if none was made.
+func (e *ExhaustedError) last() error {
+	if len(e.errs) == 0 {
+		return nil
+	}
+
+	return e.errs[len(e.errs)-1]
+}
+
+// IsExhausted reports whether err, or any error it wraps, signals that all
+// retry attempts have failed.
+//
+// Example:
+//
+//	if _, err := retry.Do(ctx, rc, fn); retry.IsExhausted(err) {
+//	    log.Println("giving up after retries")
+//	}
+func IsExhausted(err error) bool {
+	return errors.Is(err, ErrExhausted)
+}
+
 // isRetryable determines whether an error should trigger a retry attempt.
 // It returns true for network timeout errors and all errors except those
 // explicitly marked as non-retryable using NonRetryable().
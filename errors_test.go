@@ -2,7 +2,10 @@ package retry
 
 import (
 	"errors"
+	"fmt"
+	"io"
 	"testing"
+	"time"
 )
 
 // TestIsRetryableDefaultError verifies that regular errors are considered
@@ -42,3 +45,63 @@ func TestIsRetryableTimeoutError(t *testing.T) {
 		t.Error("timeout error should be retryable")
 	}
 }
+
+// TestIsIgnored verifies that only errors wrapped with IgnoreError() are
+// identified as ignorable, and that the original error stays reachable.
+func TestIsIgnored(t *testing.T) {
+	base := errors.New("key already exists")
+	err := IgnoreError(base)
+
+	if !isIgnored(err) {
+		t.Error("error wrapped with IgnoreError should be ignored")
+	}
+
+	if !errors.Is(err, base) {
+		t.Error("expected wrapped error to be reachable with errors.Is")
+	}
+
+	if isIgnored(base) {
+		t.Error("plain error should not be ignored")
+	}
+}
+
+// TestIsSuccess verifies that only errors wrapped with SuccessOnError() are
+// identified as terminal success conditions.
+func TestIsSuccess(t *testing.T) {
+	err := SuccessOnError(io.EOF)
+
+	if !isSuccess(err) {
+		t.Error("error wrapped with SuccessOnError should be a success condition")
+	}
+
+	if !errors.Is(err, io.EOF) {
+		t.Error("expected io.EOF to be reachable with errors.Is")
+	}
+
+	if isSuccess(io.EOF) {
+		t.Error("plain io.EOF should not be a success condition")
+	}
+}
+
+// TestRetryAfter verifies that RetryAfter keeps the error retryable and
+// exposes the requested delay.
+func TestRetryAfter(t *testing.T) {
+	base := errors.New("rate limited")
+	err := fmt.Errorf("request failed: %w", RetryAfter(base, 3*time.Second))
+
+	if !isRetryable(err) {
+		t.Error("error wrapped with RetryAfter should be retryable")
+	}
+
+	if !errors.Is(err, base) {
+		t.Error("expected wrapped error to be reachable with errors.Is")
+	}
+
+	if d, ok := retryAfterDelay(err); !ok || d != 3*time.Second {
+		t.Errorf("expected delay 3s, got %v (ok=%v)", d, ok)
+	}
+
+	if _, ok := retryAfterDelay(base); ok {
+		t.Error("plain error should not carry a delay")
+	}
+}
@@ -0,0 +1,45 @@
+package retry
+
+import (
+	"math"
+	"sync"
+)
+
+var (
+	fibonacciOnce sync.Once
+	fibonacciMemo []int64
+)
+
+// FibonacciSequence returns the first n Fibonacci numbers, starting with
+// 1, 1, 2, 3, 5. The sequence is computed once and memoized, so subsequent
+// calls only copy the requested prefix. The returned slice is owned by the
+// caller and may be modified freely.
+//
+// Only the 92 Fibonacci numbers that fit into an int64 are available; larger
+// values of n are truncated to that length. It returns nil for n <= 0.
+//
+// Example:
+//
+//	retry.FibonacciSequence(6) // [1 1 2 3 5 8]
+func FibonacciSequence(n int) []int64 {
+	if n <= 0 {
+		return nil
+	}
+
+	fibonacciOnce.Do(func() {
+		fibonacciMemo = []int64{1, 1}
+		for {
+			a, b := fibonacciMemo[len(fibonacciMemo)-2], fibonacciMemo[len(fibonacciMemo)-1]
+			if a > math.MaxInt64-b {
+				break
+			}
+			fibonacciMemo = append(fibonacciMemo, a+b)
+		}
+	})
+
+	n = min(n, len(fibonacciMemo))
+	seq := make([]int64, n)
+	copy(seq, fibonacciMemo)
+
+	return seq
+}
@@ -0,0 +1,41 @@
+package retry
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestFibonacciSequence verifies the first Fibonacci numbers, the handling of
+// non-positive lengths and the truncation at the int64 limit.
+func TestFibonacciSequence(t *testing.T) {
+	if got := FibonacciSequence(0); got != nil {
+		t.Errorf("expected nil for n=0, got %v", got)
+	}
+
+	want := []int64{1, 1, 2, 3, 5, 8, 13, 21}
+	if got := FibonacciSequence(len(want)); !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	all := FibonacciSequence(1000)
+	if len(all) != 92 {
+		t.Errorf("expected 92 numbers fitting into int64, got %d", len(all))
+	}
+
+	for i := 2; i < len(all); i++ {
+		if all[i] != all[i-1]+all[i-2] || all[i] <= 0 {
+			t.Fatalf("invalid Fibonacci number at index %d: %d", i, all[i])
+		}
+	}
+}
+
+// TestFibonacciSequenceIsCopy verifies that modifying a returned slice does
+// not affect the memoized sequence.
+func TestFibonacciSequenceIsCopy(t *testing.T) {
+	seq := FibonacciSequence(3)
+	seq[0] = 42
+
+	if got := FibonacciSequence(3)[0]; got != 1 {
+		t.Errorf("expected memoized sequence to be unchanged, got %d", got)
+	}
+}
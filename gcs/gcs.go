@@ -0,0 +1,49 @@
+// Package gcs provides retry classification for Google Cloud Storage
+// operations. It lives in its own module so that the Google API
+// dependencies are only pulled in by users who need them.
+package gcs
+
+import (
+	"errors"
+	"net/http"
+
+	retry "github.com/1amDudman/try-again-go"
+	"google.golang.org/api/googleapi"
+)
+
+// transientCodes lists the HTTP status codes GCS documents as transient.
+var transientCodes = map[int]bool{
+	http.StatusRequestTimeout:      true,
+	http.StatusTooManyRequests:     true,
+	http.StatusInternalServerError: true,
+	http.StatusBadGateway:          true,
+	http.StatusServiceUnavailable:  true,
+	http.StatusGatewayTimeout:      true,
+}
+
+// WithGCSRetryable returns a retry option that classifies errors returned by
+// *storage.Client operations. A *googleapi.Error is retried only for the
+// transient status codes 408, 429 and 500–504; permanent errors such as
+// 404 Not Found or 403 Forbidden stop the retry loop immediately. Errors
+// that are not *googleapi.Error keep the default behavior and are retried.
+//
+// Example:
+//
+//	rc := retry.NewRetry(gcs.WithGCSRetryable())
+//	attrs, err := retry.Do(ctx, rc, func() (*storage.ObjectAttrs, error) {
+//	    return client.Bucket("bucket").Object("name").Attrs(ctx)
+//	})
+func WithGCSRetryable() retry.Option {
+	return retry.WithRetryIf(IsRetryable)
+}
+
+// IsRetryable reports whether err is a transient GCS error according to the
+// rules documented on WithGCSRetryable.
+func IsRetryable(err error) bool {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return transientCodes[apiErr.Code]
+	}
+
+	return true
+}
@@ -0,0 +1,57 @@
+package gcs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	retry "github.com/1amDudman/try-again-go"
+	"google.golang.org/api/googleapi"
+)
+
+// TestIsRetryable verifies the classification of GCS API errors.
+func TestIsRetryable(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"rate limited", &googleapi.Error{Code: 429}, true},
+		{"internal error", &googleapi.Error{Code: 500}, true},
+		{"service unavailable", fmt.Errorf("read: %w", &googleapi.Error{Code: 503}), true},
+		{"not found", &googleapi.Error{Code: 404}, false},
+		{"forbidden", &googleapi.Error{Code: 403}, false},
+		{"other error", errors.New("connection reset"), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := IsRetryable(tc.err); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestWithGCSRetryable verifies that a permanent GCS error stops Do after
+// the first attempt.
+func TestWithGCSRetryable(t *testing.T) {
+	t.Parallel()
+	rc := retry.NewRetry(WithGCSRetryable())
+	calls := 0
+
+	_, err := retry.Do(t.Context(), rc, func() (string, error) {
+		calls++
+		return "", &googleapi.Error{Code: 404}
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
@@ -0,0 +1,115 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Group runs functions concurrently, each in its own retry loop, and waits
+// for all of them, similar to errgroup.Group. Use NewGroup() to create
+// instances, Go() to start workers and Wait() to collect their errors.
+type Group struct {
+	rc     *RetryConfig            // Retry policy of every worker
+	ctx    context.Context         // Shared context passed to every worker
+	cancel context.CancelCauseFunc // Cancels ctx
+
+	shared bool // Cancel ctx when a worker fails with a non-retryable error
+
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error // Errors of failed workers, in the order they failed
+}
+
+// GroupOption defines a function type for configuring Group using the
+// functional options pattern.
+type GroupOption func(*Group)
+
+// WithSharedContext controls whether a worker that fails with a
+// non-retryable error cancels the shared context of the group, and with it
+// the retry loops of all other workers. This is the fail-fast behavior of
+// errgroup.WithContext. Workers that exhaust their attempts do not cancel
+// the others. The default is false.
+//
+// Example:
+//
+//	g := retry.NewGroup(ctx, rc, retry.WithSharedContext(true))
+func WithSharedContext(cancel bool) GroupOption {
+	return func(g *Group) {
+		g.shared = cancel
+	}
+}
+
+// NewGroup creates a Group whose workers retry with rc. Every worker
+// receives a context derived from ctx, which is canceled when Wait returns.
+//
+// Example:
+//
+//	g := retry.NewGroup(ctx, rc, retry.WithSharedContext(true))
+//	for _, shard := range shards {
+//	    g.Go(func(ctx context.Context) error {
+//	        return migrate(ctx, shard)
+//	    })
+//	}
+//	if err := g.Wait(); err != nil {
+//	    return err
+//	}
+func NewGroup(ctx context.Context, rc *RetryConfig, opts ...GroupOption) *Group {
+	g := &Group{rc: rc}
+	g.ctx, g.cancel = context.WithCancelCause(ctx)
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	return g
+}
+
+// Go starts fn in a new goroutine and retries it with the policy of the
+// group, passing the shared context to every attempt.
+func (g *Group) Go(fn func(ctx context.Context) error) {
+	g.wg.Add(1)
+
+	go func() {
+		defer g.wg.Done()
+
+		// Attempts abandoned by WithMaxAttemptDuration may still finish
+		// after the retry loop, hence the lock.
+		var mu sync.Mutex
+		var lastErr error
+		_, err := DoWithContext(g.ctx, g.rc, func(ctx context.Context) (struct{}, error) {
+			err := fn(ctx)
+			mu.Lock()
+			lastErr = err
+			mu.Unlock()
+			return struct{}{}, err
+		})
+		if err == nil {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		g.mu.Lock()
+		g.errs = append(g.errs, err)
+		g.mu.Unlock()
+
+		if g.shared && lastErr != nil && errors.Is(err, lastErr) && !g.rc.shouldRetry(lastErr) {
+			g.cancel(err)
+		}
+	}()
+}
+
+// Wait blocks until all workers have returned, cancels the shared context
+// and returns the errors of all failed workers joined with errors.Join, or
+// nil if all of them succeeded.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel(nil)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return errors.Join(g.errs...)
+}
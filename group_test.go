@@ -0,0 +1,89 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestGroupWait verifies that every worker is retried and that the errors
+// of failed workers are joined.
+func TestGroupWait(t *testing.T) {
+	t.Parallel()
+	errDown := errors.New("shard down")
+	var flakyCalls atomic.Int32
+
+	g := NewGroup(context.Background(), NewRetry(WithAttempts(3), WithDelay(time.Millisecond)))
+	g.Go(func(ctx context.Context) error {
+		if flakyCalls.Add(1) < 3 {
+			return errors.New("busy")
+		}
+		return nil
+	})
+	g.Go(func(ctx context.Context) error {
+		return errDown
+	})
+
+	err := g.Wait()
+	if !errors.Is(err, errDown) || !errors.Is(err, ErrExhausted) {
+		t.Errorf("expected the exhausted worker's error, got %v", err)
+	}
+	if flakyCalls.Load() != 3 {
+		t.Errorf("expected the flaky worker to succeed on attempt 3, got %d calls", flakyCalls.Load())
+	}
+}
+
+// TestGroupWithSharedContext verifies that a non-retryable error cancels
+// the other workers only when the shared context is enabled.
+func TestGroupWithSharedContext(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		shared       bool
+		wantCanceled bool
+	}{
+		{name: "enabled", shared: true, wantCanceled: true},
+		{name: "disabled", shared: false, wantCanceled: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rc := NewRetry(WithAttempts(3), WithDelay(50*time.Millisecond))
+			g := NewGroup(context.Background(), rc, WithSharedContext(tc.shared))
+
+			var canceled atomic.Bool
+			started := make(chan struct{})
+			var once sync.Once
+			g.Go(func(ctx context.Context) error {
+				once.Do(func() { close(started) })
+				select {
+				case <-ctx.Done():
+					canceled.Store(true)
+					return ctx.Err()
+				case <-time.After(100 * time.Millisecond):
+					return errors.New("slow failure")
+				}
+			})
+			g.Go(func(ctx context.Context) error {
+				<-started
+				return NonRetryable(errors.New("invalid input"))
+			})
+
+			err := g.Wait()
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if canceled.Load() != tc.wantCanceled {
+				t.Errorf("expected canceled=%v, got %v", tc.wantCanceled, canceled.Load())
+			}
+			if tc.wantCanceled && !errors.Is(err, context.Canceled) {
+				t.Errorf("expected the canceled worker to report context.Canceled, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package retry
+
+import "net/http"
+
+// httpClientAttempts is the number of attempts derived by
+// NewRetryFromHTTPClient for clients with a timeout.
+const httpClientAttempts = 5
+
+// NewRetryFromHTTPClient creates a RetryConfig whose delays are derived from
+// the timeout of the given http.Client, so that the time spent sleeping
+// between attempts stays well within that timeout:
+//   - 5 attempts
+//   - base delay of Timeout/20
+//   - maximum delay of Timeout/5
+//
+// With these values the sum of all delays is at most Timeout/5 for fixed
+// delays and about half the timeout for exponential backoff. Clients
+// without a timeout get the defaults of NewRetry. The given options are
+// applied afterwards and take precedence.
+//
+// Example:
+//
+//	client := &http.Client{Timeout: 10 * time.Second}
+//	rc := retry.NewRetryFromHTTPClient(client, retry.WithDelayType(retry.ExpBackoffWithJitter()))
+func NewRetryFromHTTPClient(c *http.Client, opts ...Option) *RetryConfig {
+	if c == nil || c.Timeout <= 0 {
+		return NewRetry(opts...)
+	}
+
+	derived := []Option{
+		WithAttempts(httpClientAttempts),
+		WithDelay(c.Timeout / 20),
+		WithMaxDelay(c.Timeout / 5),
+	}
+
+	return NewRetry(append(derived, opts...)...)
+}
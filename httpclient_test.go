@@ -0,0 +1,64 @@
+package retry
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestNewRetryFromHTTPClient verifies that the derived delays keep the total
+// sleep time within the client's timeout for the built-in strategies.
+func TestNewRetryFromHTTPClient(t *testing.T) {
+	t.Parallel()
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	testCases := []struct {
+		name     string
+		strategy DelayTypeFunc
+	}{
+		{"fixed delay", FixedDelay()},
+		{"exponential backoff", ExpBackoffWithJitter()},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rc := NewRetryFromHTTPClient(client, WithDelayType(tc.strategy))
+
+			if rc.attempts != 5 {
+				t.Errorf("expected 5 attempts, got %d", rc.attempts)
+			}
+
+			if rc.baseDelay != 500*time.Millisecond || rc.maxDelay != 2*time.Second {
+				t.Errorf("expected delays 500ms/2s, got %v/%v", rc.baseDelay, rc.maxDelay)
+			}
+
+			var total time.Duration
+			for attempt := 1; attempt < rc.attempts; attempt++ {
+				total += rc.nextDelay(attempt)
+			}
+
+			if total >= client.Timeout {
+				t.Errorf("expected total delay below %v, got %v", client.Timeout, total)
+			}
+		})
+	}
+}
+
+// TestNewRetryFromHTTPClientWithoutTimeout verifies the fallback to the
+// defaults and that explicit options take precedence.
+func TestNewRetryFromHTTPClientWithoutTimeout(t *testing.T) {
+	rc := NewRetryFromHTTPClient(&http.Client{}, WithAttempts(7))
+
+	if rc.attempts != 7 {
+		t.Errorf("expected attempts to be 7, got %d", rc.attempts)
+	}
+
+	if rc.baseDelay != 100*time.Millisecond {
+		t.Errorf("expected default baseDelay, got %v", rc.baseDelay)
+	}
+
+	if rc := NewRetryFromHTTPClient(nil); rc.attempts != 3 {
+		t.Errorf("expected default attempts for nil client, got %d", rc.attempts)
+	}
+}
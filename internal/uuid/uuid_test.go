@@ -0,0 +1,20 @@
+package uuid
+
+import (
+	"regexp"
+	"testing"
+)
+
+// TestNew verifies that New returns distinct version 4 UUIDs.
+func TestNew(t *testing.T) {
+	t.Parallel()
+	v4 := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	first, second := New(), New()
+	if !v4.MatchString(first) || !v4.MatchString(second) {
+		t.Errorf("expected version 4 UUIDs, got %q and %q", first, second)
+	}
+	if first == second {
+		t.Errorf("expected distinct UUIDs, got %q twice", first)
+	}
+}
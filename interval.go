@@ -0,0 +1,147 @@
+package retry
+
+import (
+	"math"
+	"time"
+)
+
+// IntervalFunc returns the delay to wait before the next attempt and whether
+// another attempt should be made at all. It is meant for callers that manage
+// their own retry loop but want the progressive delays of a RetryConfig.
+type IntervalFunc func() (time.Duration, bool)
+
+// NewIntervalFuncFromConfig creates a stateful IntervalFunc that yields the
+// same delays Do would sleep between attempts. Call it after every failed
+// attempt: it returns false once the configured number of attempts has been
+// made, i.e. on its rc.attempts-th call. A WithDynamicAttempts function is
+// called at the start of every sequence, like at the start of every Do call.
+// The returned reset function restarts the sequence from the first attempt.
+//
+// With WithTimeBasedAttempts it yields the whole window, as it cannot see how
+// long the attempt took; subtract the duration of the attempt to keep the
+// attempt starts evenly spaced.
+//
+// Neither function is safe for concurrent use.
+//
+// Example:
+//
+//	next, reset := retry.NewIntervalFuncFromConfig(rc)
+//	for {
+//	    if err := op(); err == nil {
+//	        reset()
+//	        continue
+//	    }
+//	    delay, ok := next()
+//	    if !ok {
+//	        break
+//	    }
+//	    time.Sleep(delay)
+//	}
+func NewIntervalFuncFromConfig(rc *RetryConfig) (next IntervalFunc, reset func()) {
+	attempt, attempts := 0, 0
+
+	next = func() (time.Duration, bool) {
+		if attempt == 0 {
+			attempts = rc.maxAttempts()
+		}
+		attempt++
+		if attempt >= attempts {
+			return 0, false
+		}
+
+		return rc.delayAfter(attempt, 0), true
+	}
+
+	reset = func() {
+		attempt = 0
+	}
+
+	return next, reset
+}
+
+// Backoff yields the delays of a RetryConfig one at a time for callers that
+// run their own retry loop, e.g. inside a select statement. Use NewBackoff()
+// to create instances. A Backoff is stateful and not safe for concurrent use.
+type Backoff struct {
+	next  IntervalFunc // Yields the next delay
+	reset func()       // Restarts the sequence
+}
+
+// NewBackoff creates a Backoff that yields the same delays Do would sleep
+// between the attempts of rc.
+//
+// Example:
+//
+//	b := retry.NewBackoff(rc)
+//	for {
+//	    if err := op(); err == nil {
+//	        break
+//	    }
+//	    d, ok := b.NextDuration()
+//	    if !ok {
+//	        return errBudgetExhausted
+//	    }
+//	    timer := time.NewTimer(d)
+//	    select {
+//	    case <-ctx.Done():
+//	        timer.Stop()
+//	        return ctx.Err()
+//	    case <-timer.C:
+//	    }
+//	}
+func NewBackoff(rc *RetryConfig) *Backoff {
+	next, reset := NewIntervalFuncFromConfig(rc)
+	return &Backoff{next: next, reset: reset}
+}
+
+// NextDuration returns the delay to wait after a failed attempt. It returns
+// false once the retry budget is exhausted, i.e. after the final attempt.
+func (b *Backoff) NextDuration() (time.Duration, bool) {
+	return b.next()
+}
+
+// Reset restarts the sequence from the first attempt.
+func (b *Backoff) Reset() {
+	b.reset()
+}
+
+// DelayForAttempt returns the delay Do would sleep after the given failed
+// attempt, starting at 1. Unlike Backoff it is stateless, which suits
+// integrations that pass the attempt number to a backoff callback.
+// Strategies with jitter yield a new random sample on every call.
+//
+// Example:
+//
+//	transport.RetryBackoff = func(attempt int) time.Duration {
+//	    return retry.DelayForAttempt(rc, attempt)
+//	}
+func DelayForAttempt(rc *RetryConfig, attempt int) time.Duration {
+	return rc.nextDelay(attempt)
+}
+
+// EstimatedTotalTime returns the total time Do sleeps between attempts when
+// every attempt fails, assuming the attempts themselves take no time. It is
+// useful for deriving a timeout for the whole retry loop.
+//
+// Strategies with jitter yield a single random sample, so the result varies
+// between calls. Delays requested by the operation with RetryAfter and time
+// spent waiting for WithOffHours are not included. With
+// WithUnlimitedAttempts the result is math.MaxInt64; do not scale it.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 2*retry.EstimatedTotalTime(rc))
+//	defer cancel()
+func EstimatedTotalTime(rc *RetryConfig) time.Duration {
+	attempts := rc.maxAttempts()
+	if attempts == unlimitedAttempts {
+		return math.MaxInt64
+	}
+
+	var total time.Duration
+	for attempt := 1; attempt < attempts; attempt++ {
+		total += rc.delayAfter(attempt, 0)
+	}
+
+	return total
+}
@@ -0,0 +1,219 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestIntervalFuncStopsAfterAttempts verifies that the iterator yields the
+// configured delays and stops on its rc.attempts-th call.
+func TestIntervalFuncStopsAfterAttempts(t *testing.T) {
+	rc := NewRetry(
+		WithAttempts(4),
+		WithDelay(10*time.Millisecond),
+		WithMaxDelay(time.Second),
+		WithDelayType(func(attempt int, baseDelay, _ time.Duration) time.Duration {
+			return time.Duration(attempt) * baseDelay
+		}),
+	)
+
+	next, _ := NewIntervalFuncFromConfig(rc)
+
+	calls := 0
+	var delays []time.Duration
+	for {
+		calls++
+		delay, ok := next()
+		if !ok {
+			break
+		}
+		delays = append(delays, delay)
+	}
+
+	if calls != 4 {
+		t.Errorf("expected iteration to stop after 4 calls, got %d", calls)
+	}
+
+	want := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond}
+	if len(delays) != len(want) {
+		t.Fatalf("expected %d delays, got %v", len(want), delays)
+	}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Errorf("delay %d: expected %v, got %v", i, want[i], delays[i])
+		}
+	}
+
+	if _, ok := next(); ok {
+		t.Error("expected exhausted iterator to keep returning false")
+	}
+}
+
+// TestIntervalFuncReset verifies that reset restarts the sequence from the
+// first attempt.
+func TestIntervalFuncReset(t *testing.T) {
+	rc := NewRetry(WithAttempts(2), WithDelay(5*time.Millisecond))
+	next, reset := NewIntervalFuncFromConfig(rc)
+
+	next()
+	if _, ok := next(); ok {
+		t.Fatal("expected iterator to be exhausted")
+	}
+
+	reset()
+	if delay, ok := next(); !ok || delay != 5*time.Millisecond {
+		t.Errorf("expected (5ms, true) after reset, got (%v, %v)", delay, ok)
+	}
+}
+
+// TestIntervalFuncDynamicAttemptsAndWindow verifies that the iterator
+// resolves WithDynamicAttempts per sequence and yields the whole window of
+// WithTimeBasedAttempts.
+func TestIntervalFuncDynamicAttemptsAndWindow(t *testing.T) {
+	t.Parallel()
+	attempts := 3
+	rc := NewRetry(
+		WithDelay(5*time.Millisecond),
+		WithTimeBasedAttempts(time.Second, time.Minute),
+		WithDynamicAttempts(func() int { return attempts }),
+	)
+	next, reset := NewIntervalFuncFromConfig(rc)
+
+	count := func() int {
+		n := 0
+		for {
+			delay, ok := next()
+			if !ok {
+				return n
+			}
+			if delay != time.Second {
+				t.Errorf("expected the attempt window as delay, got %v", delay)
+			}
+			n++
+		}
+	}
+
+	if n := count(); n != 2 {
+		t.Errorf("expected 2 delays for 3 attempts, got %d", n)
+	}
+
+	attempts = 5
+	reset()
+	if n := count(); n != 4 {
+		t.Errorf("expected 4 delays for 5 attempts after reset, got %d", n)
+	}
+}
+
+// TestBackoffMatchesDo verifies that NextDuration yields the same delays Do
+// sleeps between attempts and reports exhaustion after the final attempt.
+func TestBackoffMatchesDo(t *testing.T) {
+	t.Parallel()
+	var slept []time.Duration
+	opts := []Option{
+		WithAttempts(4),
+		WithDelay(time.Millisecond),
+		WithMaxDelay(time.Second),
+		WithDelayType(func(attempt int, baseDelay, _ time.Duration) time.Duration {
+			return time.Duration(attempt*attempt) * baseDelay
+		}),
+	}
+	rc := NewRetry(opts...)
+
+	withHook := NewRetry(append(opts, WithOnRetry(func(_ int, _ error, delay time.Duration) {
+		slept = append(slept, delay)
+	}))...)
+	_, _ = Do(context.Background(), withHook, func() (int, error) {
+		return 0, errors.New("fail")
+	})
+
+	b := NewBackoff(rc)
+	var yielded []time.Duration
+	for {
+		d, ok := b.NextDuration()
+		if !ok {
+			break
+		}
+		yielded = append(yielded, d)
+	}
+
+	if !slices.Equal(yielded, slept) {
+		t.Errorf("expected Backoff to yield %v like Do, got %v", slept, yielded)
+	}
+
+	b.Reset()
+	if d, ok := b.NextDuration(); !ok || d != time.Millisecond {
+		t.Errorf("expected first delay after Reset to be 1ms, got %v, %t", d, ok)
+	}
+}
+
+// TestEstimatedTotalTime compares the estimate with manual calculations for
+// known strategies.
+func TestEstimatedTotalTime(t *testing.T) {
+	t.Parallel()
+	linear := func(attempt int, baseDelay, maxDelay time.Duration) time.Duration {
+		return min(time.Duration(attempt)*baseDelay, maxDelay)
+	}
+
+	testCases := []struct {
+		name     string
+		rc       *RetryConfig
+		expected time.Duration
+	}{
+		{
+			name:     "defaults",
+			rc:       NewRetry(),
+			expected: 2 * 100 * time.Millisecond,
+		},
+		{
+			name:     "fixed delay",
+			rc:       NewRetry(WithAttempts(5), WithDelay(time.Second)),
+			expected: 4 * time.Second,
+		},
+		{
+			name:     "linear capped at max delay",
+			rc:       NewRetry(WithAttempts(5), WithDelay(time.Second), WithMaxDelay(3*time.Second), WithDelayType(linear)),
+			expected: (1 + 2 + 3 + 3) * time.Second,
+		},
+		{
+			name:     "min delay",
+			rc:       NewRetry(WithAttempts(3), WithDelay(0), WithMinDelay(50*time.Millisecond)),
+			expected: 100 * time.Millisecond,
+		},
+		{
+			name:     "single attempt",
+			rc:       NewRetry(WithAttempts(1)),
+			expected: 0,
+		},
+		{
+			name:     "time based attempts",
+			rc:       NewRetry(WithTimeBasedAttempts(time.Second, 10*time.Second)),
+			expected: 9 * time.Second,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := EstimatedTotalTime(tc.rc); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestDelayForAttempt verifies that the delay of any attempt can be queried
+// without running a retry loop.
+func TestDelayForAttempt(t *testing.T) {
+	t.Parallel()
+	rc := NewRetry(WithDelay(100*time.Millisecond), WithMaxDelay(time.Second), WithDelayType(ExpBackoffWithBase(2, WithExpJitter(0))))
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second}
+	for i, want := range expected {
+		if got := DelayForAttempt(rc, i+1); got != want {
+			t.Errorf("attempt %d: expected %v, got %v", i+1, want, got)
+		}
+	}
+}
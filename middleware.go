@@ -0,0 +1,88 @@
+package retry
+
+import "time"
+
+// DelayMiddleware wraps a delay strategy to add cross-cutting behavior such
+// as clamping, logging or metrics without changing the strategy itself.
+type DelayMiddleware func(next DelayTypeFunc) DelayTypeFunc
+
+// MetricsRecorder receives the delays calculated by the MetricsDelay
+// middleware.
+type MetricsRecorder interface {
+	ObserveDelay(attempt int, delay time.Duration)
+}
+
+// WithDelayMiddleware wraps the configured delay strategy with the given
+// middlewares. The first middleware is the outermost one: it sees the
+// delay after all following middlewares have been applied. Middlewares
+// apply regardless of whether WithDelayType is set before or after them;
+// delays from WithDynamicDelay are not wrapped.
+//
+// Example:
+//
+//	retry.NewRetry(
+//	    retry.WithDelayType(retry.ExpBackoffWithJitter()),
+//	    retry.WithDelayMiddleware(
+//	        retry.LogDelay(logger),
+//	        retry.ClampDelay(50*time.Millisecond, 2*time.Second),
+//	    ),
+//	)
+func WithDelayMiddleware(mw ...DelayMiddleware) Option {
+	return func(rc *RetryConfig) {
+		rc.delayMiddleware = append(rc.delayMiddleware, mw...)
+	}
+}
+
+// wrapDelay applies the configured middlewares to strategy.
+func (rc *RetryConfig) wrapDelay(strategy DelayTypeFunc) DelayTypeFunc {
+	for i := len(rc.delayMiddleware) - 1; i >= 0; i-- {
+		strategy = rc.delayMiddleware[i](strategy)
+	}
+
+	return strategy
+}
+
+// ClampDelay returns a middleware that keeps delays between minDelay and
+// maxDelay.
+//
+// Example:
+//
+//	retry.WithDelayMiddleware(retry.ClampDelay(10*time.Millisecond, time.Second))
+func ClampDelay(minDelay, maxDelay time.Duration) DelayMiddleware {
+	return func(next DelayTypeFunc) DelayTypeFunc {
+		return func(attempt int, baseDelay, maxDelayCfg time.Duration) time.Duration {
+			return min(max(next(attempt, baseDelay, maxDelayCfg), minDelay), maxDelay)
+		}
+	}
+}
+
+// LogDelay returns a middleware that logs every calculated delay.
+//
+// Example:
+//
+//	retry.WithDelayMiddleware(retry.LogDelay(log.Default()))
+func LogDelay(logger Logger) DelayMiddleware {
+	return func(next DelayTypeFunc) DelayTypeFunc {
+		return func(attempt int, baseDelay, maxDelay time.Duration) time.Duration {
+			delay := next(attempt, baseDelay, maxDelay)
+			logger.Printf("Calculated delay for attempt %d: %v", attempt, delay)
+			return delay
+		}
+	}
+}
+
+// MetricsDelay returns a middleware that reports every calculated delay to
+// recorder.
+//
+// Example:
+//
+//	retry.WithDelayMiddleware(retry.MetricsDelay(prometheusRecorder))
+func MetricsDelay(recorder MetricsRecorder) DelayMiddleware {
+	return func(next DelayTypeFunc) DelayTypeFunc {
+		return func(attempt int, baseDelay, maxDelay time.Duration) time.Duration {
+			delay := next(attempt, baseDelay, maxDelay)
+			recorder.ObserveDelay(attempt, delay)
+			return delay
+		}
+	}
+}
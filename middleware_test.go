@@ -0,0 +1,69 @@
+package retry
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// recordingMetrics is a MetricsRecorder that stores every observed delay.
+type recordingMetrics struct {
+	delays []time.Duration
+}
+
+func (r *recordingMetrics) ObserveDelay(_ int, delay time.Duration) {
+	r.delays = append(r.delays, delay)
+}
+
+// TestDelayMiddlewareComposition verifies that the first middleware is the
+// outermost one and sees the delays produced by the following ones.
+func TestDelayMiddlewareComposition(t *testing.T) {
+	t.Parallel()
+	linear := func(attempt int, baseDelay, _ time.Duration) time.Duration {
+		return time.Duration(attempt) * baseDelay
+	}
+
+	outer := &recordingMetrics{}
+	inner := &recordingMetrics{}
+	logger := &recordingLogger{}
+	rc := NewRetry(
+		WithDelay(10*time.Millisecond),
+		WithMaxDelay(time.Second),
+		WithDelayMiddleware(MetricsDelay(outer), ClampDelay(15*time.Millisecond, 25*time.Millisecond)),
+		WithDelayMiddleware(MetricsDelay(inner), LogDelay(logger)),
+		WithDelayType(linear),
+	)
+
+	var got []time.Duration
+	for attempt := 1; attempt <= 3; attempt++ {
+		got = append(got, rc.nextDelay(attempt))
+	}
+
+	want := []time.Duration{15 * time.Millisecond, 20 * time.Millisecond, 25 * time.Millisecond}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected clamped delays %v, got %v", want, got)
+	}
+	if !slices.Equal(outer.delays, want) {
+		t.Errorf("expected the outer recorder to see %v, got %v", want, outer.delays)
+	}
+
+	unclamped := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond}
+	if !slices.Equal(inner.delays, unclamped) {
+		t.Errorf("expected the inner recorder to see %v, got %v", unclamped, inner.delays)
+	}
+	if len(logger.lines) != 3 || logger.lines[0] != "Calculated delay for attempt 1: 10ms" {
+		t.Errorf("expected 3 log lines, got %q", logger.lines)
+	}
+}
+
+// TestDelayMiddlewareWithoutStrategy verifies that middlewares also wrap the
+// base delay fallback.
+func TestDelayMiddlewareWithoutStrategy(t *testing.T) {
+	t.Parallel()
+	rc := NewRetry(WithDelay(time.Second), WithDelayType(nil),
+		WithDelayMiddleware(ClampDelay(0, 100*time.Millisecond)))
+
+	if got := rc.nextDelay(1); got != 100*time.Millisecond {
+		t.Errorf("expected 100ms, got %v", got)
+	}
+}
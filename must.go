@@ -0,0 +1,74 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+)
+
+// DoVoid executes fn like Do for operations that only return an error.
+//
+// Example:
+//
+//	err := retry.DoVoid(ctx, config, func() error {
+//	    return db.PingContext(ctx)
+//	})
+func DoVoid(ctx context.Context, rc *RetryConfig, fn func() error) error {
+	_, err := Do(ctx, rc, func() (struct{}, error) {
+		return struct{}{}, fn()
+	})
+
+	return err
+}
+
+// WrapWithBackoff executes fn with the named policy, e.g. PolicyStandard or
+// a policy registered in DefaultRegistry. It is the shortest way to retry a
+// one-off operation and deliberately takes neither a context nor options;
+// use DoVoid for anything else. An unknown name is reported with an error
+// wrapping ErrPolicyNotFound without calling fn.
+//
+// Warning: WrapWithBackoff runs with context.Background(), so nothing can
+// cancel it. If the policy never runs out of attempts, e.g. because fn keeps
+// returning errors marked with SoftRetry, it runs forever.
+//
+// Example:
+//
+//	err := retry.WrapWithBackoff(expensiveOp, retry.PolicyStandard)
+func WrapWithBackoff(fn func() error, policy PolicyName) error {
+	rc, err := policy.lookup()
+	if err != nil {
+		return err
+	}
+
+	return DoVoid(context.Background(), rc, fn)
+}
+
+// MustDo executes fn with a retry policy built from opts and returns its
+// result. It panics with an error wrapping the final error if the operation
+// never succeeds. It is meant for initialization code that cannot proceed
+// without the result, such as opening a database at startup.
+//
+// Example:
+//
+//	var db = retry.MustDo(context.Background(), func() (*sql.DB, error) {
+//	    return openDatabase()
+//	}, retry.WithAttempts(10), retry.WithDelay(time.Second))
+func MustDo[T any](ctx context.Context, fn func() (T, error), opts ...Option) T {
+	data, err := Do(ctx, NewRetry(opts...), fn)
+	if err != nil {
+		panic(fmt.Errorf("retry: operation did not succeed: %w", err))
+	}
+
+	return data
+}
+
+// MustDoVoid is the variant of MustDo for operations that only return an
+// error. It panics if the operation never succeeds.
+//
+// Example:
+//
+//	retry.MustDoVoid(ctx, migrateSchema, retry.WithAttempts(5))
+func MustDoVoid(ctx context.Context, fn func() error, opts ...Option) {
+	if err := DoVoid(ctx, NewRetry(opts...), fn); err != nil {
+		panic(fmt.Errorf("retry: operation did not succeed: %w", err))
+	}
+}
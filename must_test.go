@@ -0,0 +1,103 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestDoVoid verifies that DoVoid retries until the function succeeds.
+func TestDoVoid(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	err := DoVoid(context.Background(), NewRetry(WithDelay(time.Millisecond)), func() error {
+		calls++
+		if calls < 2 {
+			return errors.New("fail")
+		}
+		return nil
+	})
+	if err != nil || calls != 2 {
+		t.Errorf("expected success after 2 calls, got %d calls, %v", calls, err)
+	}
+}
+
+// TestMustDo verifies that MustDo returns the result on success and panics
+// with the final error otherwise.
+func TestMustDo(t *testing.T) {
+	t.Parallel()
+	if got := MustDo(context.Background(), func() (int, error) { return 42, nil }); got != 42 {
+		t.Errorf("expected 42, got %d", got)
+	}
+
+	target := errors.New("database unavailable")
+	defer func() {
+		err, ok := recover().(error)
+		if !ok || !errors.Is(err, target) {
+			t.Errorf("expected panic with error wrapping %v, got %v", target, err)
+		}
+	}()
+
+	MustDo(context.Background(), func() (int, error) {
+		return 0, target
+	}, WithAttempts(2), WithDelay(time.Millisecond))
+	t.Error("expected MustDo to panic")
+}
+
+// TestMustDoVoid verifies that MustDoVoid panics when every attempt fails.
+func TestMustDoVoid(t *testing.T) {
+	t.Parallel()
+	MustDoVoid(context.Background(), func() error { return nil })
+
+	defer func() {
+		if err, ok := recover().(error); !ok || !errors.Is(err, ErrExhausted) {
+			t.Errorf("expected panic with ErrExhausted, got %v", err)
+		}
+	}()
+
+	MustDoVoid(context.Background(), func() error {
+		return errors.New("fail")
+	}, WithAttempts(2), WithDelay(time.Millisecond))
+	t.Error("expected MustDoVoid to panic")
+}
+
+// TestWrapWithBackoff verifies that built-in and registered policies are
+// resolved by name and that unknown names fail without calling fn.
+func TestWrapWithBackoff(t *testing.T) {
+	t.Parallel()
+	errFlaky := errors.New("flaky")
+	name := PolicyName(t.Name())
+	if err := DefaultRegistry.Register(string(name), NewRetry(WithAttempts(4), WithDelay(time.Millisecond))); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name          string
+		policy        PolicyName
+		expectedCalls int
+		err           error
+	}{
+		{name: "standard", policy: PolicyStandard, expectedCalls: 3, err: errFlaky},
+		{name: "registered", policy: name, expectedCalls: 4, err: errFlaky},
+		{name: "unknown", policy: "unknown", expectedCalls: 0, err: ErrPolicyNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			calls := 0
+			err := WrapWithBackoff(func() error {
+				calls++
+				return errFlaky
+			}, tc.policy)
+
+			if !errors.Is(err, tc.err) {
+				t.Errorf("expected %v, got %v", tc.err, err)
+			}
+			if calls != tc.expectedCalls {
+				t.Errorf("expected %d calls, got %d", tc.expectedCalls, calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package retry
+
+import (
+	"errors"
+	"net"
+	"syscall"
+)
+
+// WithNetworkErrorClassifier sets a retry predicate that classifies the
+// common Go network errors. It replaces any predicate set with WithRetryIf.
+//
+// Retryable errors:
+//   - net.ErrClosed
+//   - connection refused (syscall.ECONNREFUSED)
+//   - connection reset by peer (syscall.ECONNRESET)
+//   - broken pipe (syscall.EPIPE)
+//   - DNS failures (*net.DNSError)
+//   - network timeouts
+//
+// Non-retryable errors:
+//   - address not available (syscall.EADDRNOTAVAIL)
+//   - any other *net.OpError that is not a timeout
+//
+// Errors that do not originate from the net package keep the default
+// behavior and are retried.
+//
+// Example:
+//
+//	retry.NewRetry(retry.WithNetworkErrorClassifier())
+func WithNetworkErrorClassifier() Option {
+	return WithRetryIf(isTransientNetworkError)
+}
+
+// transientNetworkErrors lists network errors that usually resolve on their
+// own and are worth retrying.
+var transientNetworkErrors = []error{
+	net.ErrClosed,
+	syscall.ECONNREFUSED,
+	syscall.ECONNRESET,
+	syscall.EPIPE,
+}
+
+// permanentNetworkErrors lists network errors that will not resolve by
+// retrying the same operation.
+var permanentNetworkErrors = []error{
+	syscall.EADDRNOTAVAIL,
+}
+
+// isTransientNetworkError reports whether err should be retried according
+// to the rules documented on WithNetworkErrorClassifier.
+func isTransientNetworkError(err error) bool {
+	for _, target := range permanentNetworkErrors {
+		if errors.Is(err, target) {
+			return false
+		}
+	}
+
+	for _, target := range transientNetworkErrors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return opErr.Timeout()
+	}
+
+	return true
+}
@@ -0,0 +1,58 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+)
+
+// TestIsTransientNetworkError verifies the classification of common network
+// errors by the predicate behind WithNetworkErrorClassifier.
+func TestIsTransientNetworkError(t *testing.T) {
+	t.Parallel()
+
+	opErr := func(errno syscall.Errno) error {
+		return &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", errno)}
+	}
+
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"closed connection", fmt.Errorf("read: %w", net.ErrClosed), true},
+		{"connection refused", opErr(syscall.ECONNREFUSED), true},
+		{"connection reset", opErr(syscall.ECONNRESET), true},
+		{"broken pipe", opErr(syscall.EPIPE), true},
+		{"dns failure", &net.DNSError{Err: "no such host", Name: "example.invalid"}, true},
+		{"address not available", opErr(syscall.EADDRNOTAVAIL), false},
+		{"other op error", opErr(syscall.EACCES), false},
+		{"non-network error", errors.New("some error"), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := isTransientNetworkError(tc.err); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestWithNetworkErrorClassifier verifies that the option installs the
+// network classifier as the retry predicate.
+func TestWithNetworkErrorClassifier(t *testing.T) {
+	r := NewRetry(WithNetworkErrorClassifier())
+
+	if r.shouldRetry(&net.OpError{Op: "dial", Err: syscall.EADDRNOTAVAIL}) {
+		t.Error("expected EADDRNOTAVAIL to not be retryable")
+	}
+
+	if !r.shouldRetry(&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}) {
+		t.Error("expected ECONNREFUSED to be retryable")
+	}
+}
@@ -1,7 +1,13 @@
 package retry
 
 import (
+	"context"
+	"io"
+	"maps"
+	"math"
 	rand "math/rand/v2"
+	"slices"
+	"sync"
 	"time"
 )
 
@@ -12,7 +18,8 @@ type Option func(*RetryConfig)
 
 // WithAttempts sets the number of retry attempts for the RetryConfig.
 // The attempts value determines how many times the operation will be
-// retried before giving up. Must be a positive integer.
+// retried before giving up. Must be a positive integer; use
+// WithUnlimitedAttempts to retry until the context is done.
 //
 // Example:
 //
@@ -23,6 +30,34 @@ func WithAttempts(attempts int) Option {
 	}
 }
 
+// unlimitedAttempts is the number of attempts set by WithUnlimitedAttempts.
+// It is never reached in practice.
+const unlimitedAttempts = math.MaxInt
+
+// WithUnlimitedAttempts retries until the operation succeeds, returns an
+// error that is not retried, or the context is done. It overrides
+// WithAttempts and suits long-lived loops such as reconnecting to a
+// server. Always pass a context that is canceled on shutdown, or bound the
+// loop with WithMaxElapsedTime. Progress lines and structured logs report
+// the maximum number of attempts as 0. Unless WithMultiError is set, only
+// the error of the last attempt is kept, so memory does not grow with the
+// number of attempts.
+//
+// Example:
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+//	defer stop()
+//	conn, err := retry.Do(ctx, retry.NewRetry(
+//	    retry.WithUnlimitedAttempts(),
+//	    retry.WithDelayType(retry.ExpBackoffWithJitter()),
+//	    retry.WithMaxDelay(30*time.Second),
+//	), connect)
+func WithUnlimitedAttempts() Option {
+	return func(rc *RetryConfig) {
+		rc.attempts = unlimitedAttempts
+	}
+}
+
 // WithDelay sets the base delay duration between retry attempts.
 // This delay is used as the foundation for delay calculations in
 // both fixed and exponential backoff strategies.
@@ -49,6 +84,20 @@ func WithMaxDelay(maxDelay time.Duration) Option {
 	}
 }
 
+// WithMinDelay sets the minimum delay between retry attempts. Delays
+// computed by the delay strategy or WithDynamicDelay that are shorter than
+// d are raised to d, so a strategy returning values near zero cannot
+// overwhelm the target with immediate retries.
+//
+// Example:
+//
+//	retry.NewRetry(retry.WithMinDelay(50*time.Millisecond))
+func WithMinDelay(d time.Duration) Option {
+	return func(rc *RetryConfig) {
+		rc.minDelay = d
+	}
+}
+
 // WithDelayType sets the delay calculation function for retry attempts.
 // This allows customization of the delay strategy (fixed, exponential, etc.).
 // The function receives the attempt number, base delay, and max delay.
@@ -79,23 +128,600 @@ func WithLogger(logger Logger) Option {
 // WithOnRetry sets a hook for retry operations especially for metrics. The onRetry will
 // receive detailed information about retry attempts, failures, and timing.
 // Use this to integrate retry hook with your application's metrics system.
+// It is called after every failed attempt that will be retried, right
+// before the delay, with the number of the failed attempt, its error and
+// the upcoming delay. It is not called after the final attempt.
 //
 // Example:
 //
-// retry.NewRetry(
-//
-//	retry.WithOnRetry(func(attempt int, err error, delay time.Duration) {
-//	    metrics.IncRetryCount(err.Error())
-//	    metrics.AddSleepTime(delay.Seconds())
-//	}),
-//
-// )
+//	retry.NewRetry(
+//	    retry.WithOnRetry(func(attempt int, err error, delay time.Duration) {
+//	        metrics.IncRetryCount(err.Error())
+//	        metrics.AddSleepTime(delay.Seconds())
+//	    }),
+//	)
 func WithOnRetry(fn OnRetryFunc) Option {
 	return func(c *RetryConfig) {
 		c.onRetry = fn
 	}
 }
 
+// WithRetryIf sets a predicate that centrally decides which errors are
+// retryable. Errors for which the predicate returns false stop the retry
+// loop immediately. Errors marked with NonRetryable() are never retried,
+// regardless of the predicate.
+//
+// Example:
+//
+//	retry.NewRetry(retry.WithRetryIf(func(err error) bool {
+//	    return errors.Is(err, ErrServiceUnavailable)
+//	}))
+func WithRetryIf(fn RetryIfFunc) Option {
+	return func(rc *RetryConfig) {
+		rc.retryIf = fn
+	}
+}
+
+// WithMaxAttemptDuration caps the execution time of every single attempt
+// without relying on context cancellation. This is meant for operations that
+// ignore context, such as legacy code or blocking system calls.
+//
+// Each attempt runs in its own goroutine. If it has not returned after d,
+// the attempt fails with ErrAttemptTimedOut (which is retryable) and the
+// function set with WithAbortFunc is called from a dedicated goroutine.
+//
+// Trade-offs to be aware of:
+//   - An abandoned attempt keeps running in the background until it returns;
+//     Go cannot forcibly stop a goroutine. Use WithAbortFunc to unblock it,
+//     e.g. by closing a connection or file descriptor, or DoWithContext,
+//     which passes each attempt a context that times out with it.
+//   - An abandoned attempt may still be running while the next one starts,
+//     so the retry function must be safe to run concurrently with itself.
+//   - Results of abandoned attempts are discarded.
+//   - Each attempt costs an extra goroutine and timer.
+//
+// Example:
+//
+//	retry.NewRetry(
+//	    retry.WithMaxAttemptDuration(5*time.Second),
+//	    retry.WithAbortFunc(func(int) { conn.Close() }),
+//	)
+func WithMaxAttemptDuration(d time.Duration) Option {
+	return func(rc *RetryConfig) {
+		rc.maxAttemptDuration = d
+	}
+}
+
+// WithAbortFunc sets the function called when an attempt exceeds the
+// duration configured with WithMaxAttemptDuration. It receives the number of
+// the abandoned attempt and runs in its own goroutine, so it must be safe
+// for concurrent use.
+func WithAbortFunc(fn func(attempt int)) Option {
+	return func(rc *RetryConfig) {
+		rc.abortFunc = fn
+	}
+}
+
+// WithTimeBasedAttempts configures retries by time instead of by count.
+// Attempts start every window, independent of how long each attempt takes,
+// and the number of attempts is derived as budget / window (at least one).
+// The delay after a failed attempt is the remainder of its window, so the
+// retry loop finishes close to budget.
+//
+// The attempt count overrides WithAttempts, and while the window is set Do
+// ignores the delay strategy, since the spacing is fixed by window.
+//
+// Example:
+//
+//	// Try every 2 seconds for up to a minute (30 attempts).
+//	retry.NewRetry(retry.WithTimeBasedAttempts(2*time.Second, time.Minute))
+func WithTimeBasedAttempts(window, budget time.Duration) Option {
+	return func(rc *RetryConfig) {
+		rc.attemptWindow = window
+		rc.attempts = 1
+		if window > 0 {
+			rc.attempts = max(int(budget/window), 1)
+		}
+		rc.baseDelay = window
+		rc.maxDelay = window
+	}
+}
+
+// WithDynamicAttempts sets a function that provides the number of attempts
+// at runtime. It is called once at the start of every Do call, not per
+// attempt, and takes precedence over WithAttempts. This allows reducing
+// retries while a downstream service is known to be degraded. The function
+// must be safe for concurrent use if the config is shared between goroutines.
+//
+// Example:
+//
+//	var maxAttempts atomic.Int64
+//	maxAttempts.Store(5)
+//	retry.NewRetry(retry.WithDynamicAttempts(func() int {
+//	    return int(maxAttempts.Load())
+//	}))
+func WithDynamicAttempts(fn func() int) Option {
+	return func(rc *RetryConfig) {
+		rc.dynamicAttempts = fn
+	}
+}
+
+// WithDynamicDelay sets a function that provides the delay after a failed
+// attempt at runtime. Unlike a DelayTypeFunc, which computes delays from the
+// values captured at construction, fn is expected to read the current
+// delay, e.g. from a config service, and is called right before each sleep.
+// It takes precedence over the delay strategy and its result is not capped
+// by maxDelay. The function must be safe for concurrent use if the config is
+// shared between goroutines.
+//
+// Example:
+//
+//	retry.NewRetry(retry.WithDynamicDelay(func(attempt int) time.Duration {
+//	    return settings.RetryDelay() * time.Duration(attempt)
+//	}))
+func WithDynamicDelay(fn func(attempt int) time.Duration) Option {
+	return func(rc *RetryConfig) {
+		rc.dynamicDelay = fn
+	}
+}
+
+// WithFixedAttemptDelay sets an explicit delay schedule: the n-th element
+// is the delay after the n-th failed attempt, and the last element is
+// repeated once the schedule runs out. Like WithDynamicDelay it takes
+// precedence over the delay strategy and is not capped by maxDelay. An
+// empty schedule leaves the configuration unchanged.
+//
+// Example:
+//
+//	// Retry immediately, then after 1s, 5s and every 30s.
+//	retry.NewRetry(
+//	    retry.WithAttempts(6),
+//	    retry.WithFixedAttemptDelay([]time.Duration{0, time.Second, 5 * time.Second, 30 * time.Second}),
+//	)
+func WithFixedAttemptDelay(delays []time.Duration) Option {
+	if len(delays) == 0 {
+		return func(*RetryConfig) {}
+	}
+	delays = slices.Clone(delays)
+
+	return WithDynamicDelay(func(attempt int) time.Duration {
+		return delays[min(max(attempt, 1), len(delays))-1]
+	})
+}
+
+// WithErrorWindow enables early exit based on the pattern of recent errors.
+// After every failed attempt that would be retried, the error is added to a
+// sliding window holding the last n errors. Once the window is full, fn is
+// called with a copy of it, oldest error first; returning false aborts the
+// retry loop with the current error.
+//
+// This helps to detect systematic failures, e.g. the same error repeated n
+// times, that are unlikely to be resolved by further retries.
+//
+// Example:
+//
+//	// Give up if the last 3 errors were all the same.
+//	retry.NewRetry(retry.WithErrorWindow(3, func(errs []error) bool {
+//	    for _, err := range errs[1:] {
+//	        if err.Error() != errs[0].Error() {
+//	            return true
+//	        }
+//	    }
+//	    return false
+//	}))
+func WithErrorWindow(n int, fn func(errors []error) bool) Option {
+	return func(rc *RetryConfig) {
+		rc.errorWindowSize = n
+		rc.errorWindowFunc = fn
+	}
+}
+
+// WithOffHours restricts retries to the times accepted by allowFn, e.g.
+// business hours for batch jobs. Before every retry delay, Do checks
+// allowFn with the current time; while it returns false, Do waits, checking
+// again once a minute, until it returns true or the context is canceled.
+// The first attempt is not affected.
+//
+// Example:
+//
+//	retry.NewRetry(retry.WithOffHours(func(t time.Time) bool {
+//	    return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday &&
+//	        t.Hour() >= 9 && t.Hour() < 17
+//	}))
+func WithOffHours(allowFn func(time.Time) bool) Option {
+	return func(rc *RetryConfig) {
+		rc.allowFn = allowFn
+	}
+}
+
+// WithDelayObserver sets a function that receives the planned delay and the
+// actually measured sleep duration after every completed delay between
+// attempts. On overloaded systems timers fire late, so comparing both values
+// helps to diagnose retry loops that are slower than expected. Delays
+// interrupted by context cancellation are not reported.
+//
+// Example:
+//
+//	retry.NewRetry(retry.WithDelayObserver(func(planned, actual time.Duration) {
+//	    metrics.ObserveSleepOverrun((actual - planned).Seconds())
+//	}))
+func WithDelayObserver(fn func(planned, actual time.Duration)) Option {
+	return func(rc *RetryConfig) {
+		rc.delayObserver = fn
+	}
+}
+
+// WithRecoverPanics enables recovering from panics raised by the retry
+// function. A recovered panic is converted into an error wrapping
+// ErrPanicked and is retried like any other error. Panics are not recovered
+// by default.
+//
+// Example:
+//
+//	retry.NewRetry(retry.WithRecoverPanics(true))
+func WithRecoverPanics(enabled bool) Option {
+	return func(rc *RetryConfig) {
+		rc.recoverPanics = enabled
+	}
+}
+
+// WithPanicHandler sets a function that is called with the attempt number
+// and the recovered value whenever a panic is recovered, before it is
+// converted into an error. It is called from the goroutine that panicked, so
+// debug.Stack() returns the stack of the panic. The handler has no effect
+// unless WithRecoverPanics is enabled.
+//
+// Example:
+//
+//	retry.NewRetry(
+//	    retry.WithRecoverPanics(true),
+//	    retry.WithPanicHandler(func(attempt int, recovered any) {
+//	        log.Printf("attempt %d panicked: %v\n%s", attempt, recovered, debug.Stack())
+//	    }),
+//	)
+func WithPanicHandler(fn func(attempt int, recovered any)) Option {
+	return func(rc *RetryConfig) {
+		rc.panicHandler = fn
+	}
+}
+
+// WithContextTagger sets a function that derives the context passed to each
+// attempt of DoWithContext, e.g. to attach the attempt number or a trace ID.
+// The function always receives the context given to DoWithContext, never the
+// context it produced for a previous attempt, so values do not accumulate.
+// Do ignores the tagger, as its retry function takes no context.
+//
+// Example:
+//
+//	retry.NewRetry(retry.WithContextTagger(func(ctx context.Context, attempt int) context.Context {
+//	    return context.WithValue(ctx, attemptKey{}, attempt)
+//	}))
+func WithContextTagger(fn func(ctx context.Context, attempt int) context.Context) Option {
+	return func(rc *RetryConfig) {
+		rc.contextTagger = fn
+	}
+}
+
+// WithContextValues adds the given key-value pairs to the context passed to
+// each attempt of DoWithContext, e.g. trace IDs or feature flags that are
+// known up front. It is a shorthand for a WithContextTagger that only calls
+// context.WithValue. The values are added before the tagger runs, so the
+// tagger sees them. The map is copied; later changes to it have no effect.
+//
+// Example:
+//
+//	retry.NewRetry(retry.WithContextValues(map[any]any{
+//	    traceIDKey{}: traceID,
+//	    userIDKey{}:  userID,
+//	}))
+func WithContextValues(vals map[any]any) Option {
+	return func(rc *RetryConfig) {
+		rc.contextValues = maps.Clone(vals)
+	}
+}
+
+// WithProgressWriter writes a live progress indicator to w, typically
+// os.Stderr of a CLI. Before every attempt the current terminal line is
+// overwritten using ANSI escape codes; a newline is written when Do returns.
+// Use ProgressFormatDefault, ProgressFormatMinimal or a custom ProgressFormat.
+//
+// Example:
+//
+//	retry.NewRetry(retry.WithProgressWriter(os.Stderr, retry.ProgressFormatDefault))
+func WithProgressWriter(w io.Writer, format ProgressFormat) Option {
+	return func(rc *RetryConfig) {
+		rc.progress = w
+		rc.progressFormat = format
+	}
+}
+
+// WithAttemptCost sets a function that returns the cost of the given
+// attempt, e.g. the price of an API call or the compute it consumes. Costs
+// are summed before every attempt and checked against the budget set with
+// WithTotalCostBudget; without a budget the cost function is not called.
+//
+// Example:
+//
+//	retry.NewRetry(
+//	    retry.WithAttempts(10),
+//	    retry.WithAttemptCost(func(attempt int) float64 { return 0.02 * float64(attempt) }),
+//	    retry.WithTotalCostBudget(0.5),
+//	)
+func WithAttemptCost(costFn func(attempt int) float64) Option {
+	return func(rc *RetryConfig) {
+		rc.attemptCost = costFn
+	}
+}
+
+// WithTotalCostBudget sets the maximum cumulative cost of all attempts as
+// calculated by WithAttemptCost. An attempt whose cost would push the total
+// above the budget is not made, even if attempts remain, and an error
+// wrapping ErrCostBudgetExceeded is returned. 0 disables the budget.
+//
+// Example:
+//
+//	retry.NewRetry(retry.WithAttemptCost(costOf), retry.WithTotalCostBudget(100))
+func WithTotalCostBudget(budget float64) Option {
+	return func(rc *RetryConfig) {
+		rc.costBudget = budget
+	}
+}
+
+// WithFailFast stops retrying immediately when the first attempt fails and
+// took longer than the base delay. A slow first failure usually means the
+// service is down rather than suffering a transient blip, so waiting for all
+// remaining attempts would only delay the inevitable error.
+//
+// Example:
+//
+//	retry.NewRetry(retry.WithDelay(500*time.Millisecond), retry.WithFailFast())
+func WithFailFast() Option {
+	return func(rc *RetryConfig) {
+		rc.failFast = true
+	}
+}
+
+// WithMaxConcurrentAttempts limits the number of operations DoAll runs at
+// the same time to n. With n set to 1 the operations run one after another.
+// 0 means no limit, which is the default.
+//
+// Example:
+//
+//	retry.DoAll(ctx, fns, retry.WithMaxConcurrentAttempts(4))
+func WithMaxConcurrentAttempts(n int) Option {
+	return func(rc *RetryConfig) {
+		rc.maxConcurrent = n
+	}
+}
+
+// WithDeadlineMargin stops retrying when the context deadline is d or less
+// away before an attempt. If the next attempt cannot finish in time anyway,
+// starting it only wastes resources. Do then returns an error wrapping
+// context.DeadlineExceeded without making the attempt. The check is also
+// made before each delay, so Do does not sleep into the margin. Contexts
+// without a deadline are not affected.
+//
+// Example:
+//
+//	// Each attempt takes up to 300ms.
+//	retry.NewRetry(retry.WithDeadlineMargin(300 * time.Millisecond))
+func WithDeadlineMargin(d time.Duration) Option {
+	return func(rc *RetryConfig) {
+		rc.deadlineMargin = d
+	}
+}
+
+// WithSleepFunc replaces the sleep between attempts with fn. It is the
+// minimal seam for tests that should not wait for real delays, without
+// providing a full clock as with WithClock; the one given last takes
+// effect. Unlike the default sleep, fn is not interrupted when the context
+// is canceled; the context is checked before and after it.
+//
+// Example:
+//
+//	var slept []time.Duration
+//	retry.NewRetry(retry.WithSleepFunc(func(d time.Duration) {
+//	    slept = append(slept, d)
+//	}))
+func WithSleepFunc(fn func(time.Duration)) Option {
+	return func(rc *RetryConfig) {
+		rc.sleepFn = fn
+	}
+}
+
+// maxSkippedAttempts is the number of consecutive attempts the filter set
+// with WithAttemptFilter may skip before Do gives up.
+const maxSkippedAttempts = 1 << 16
+
+// WithAttemptFilter sets a function that decides, after a failed attempt,
+// whether the upcoming attempt is made. It receives the number of the
+// upcoming attempt and the error of the failed one. Returning false skips
+// that attempt together with its delay while still consuming it from the
+// budget; the filter is then asked about the following attempt. Unlike
+// WithRetryIf, which only inspects the error, the decision can depend on the
+// attempt number. Soft retries are not filtered.
+//
+// The filter may skip at most 65536 attempts in a row. Once it has rejected
+// that many, e.g. because it always returns false while
+// WithUnlimitedAttempts is set, Do gives up with an *ExhaustedError
+// carrying the error of the last attempt made.
+//
+// Example:
+//
+//	// Only make odd-numbered attempts, e.g. for A/B failover.
+//	retry.NewRetry(retry.WithAttemptFilter(func(attempt int, err error) bool {
+//	    return attempt%2 == 1
+//	}))
+func WithAttemptFilter(fn func(attempt int, err error) bool) Option {
+	return func(rc *RetryConfig) {
+		rc.attemptFilter = fn
+	}
+}
+
+// WithDelayCap sets a function that returns the maximum delay after the
+// given failed attempt. It takes precedence over WithMaxDelay: the returned
+// cap is passed to the delay strategy as its maximum delay and the
+// strategy's result is limited to it. Delays from WithDynamicDelay are not
+// capped.
+//
+// Example:
+//
+//	// Cap at 1s for the first 3 attempts, then at 30s.
+//	retry.NewRetry(retry.WithDelayCap(func(attempt int) time.Duration {
+//	    if attempt <= 3 {
+//	        return time.Second
+//	    }
+//	    return 30 * time.Second
+//	}))
+func WithDelayCap(fn func(attempt int) time.Duration) Option {
+	return func(rc *RetryConfig) {
+		rc.delayCap = fn
+	}
+}
+
+// WithMutex makes a configuration that is shared by concurrent Do calls
+// serialize the user-provided functions it calls, so that they do not need
+// their own synchronization. The RetryConfig itself is never modified by Do
+// and is safe for concurrent use without this option.
+//
+// Protected operations:
+//   - delay calculation, including WithDelayType, WithDynamicDelay,
+//     WithDelayCap and delay middlewares
+//   - the WithOnRetry hook
+//   - the WithDelayObserver function
+//   - the WithPanicHandler function
+//
+// The retry function itself, loggers and other hooks are not serialized.
+//
+// Example:
+//
+//	stats := map[string]int{} // Not safe for concurrent use
+//	shared := retry.NewRetry(
+//	    retry.WithMutex(),
+//	    retry.WithOnRetry(func(attempt int, err error, delay time.Duration) {
+//	        stats[err.Error()]++
+//	    }),
+//	)
+func WithMutex() Option {
+	return func(rc *RetryConfig) {
+		rc.mu = &sync.Mutex{}
+	}
+}
+
+// WithPerAttemptLogger sets a function that selects the logger for each
+// attempt, starting at 1, e.g. to log the first attempt to stdout and later
+// ones to an alerting system. Messages about an attempt, including the
+// final exhaustion message, go to the logger returned for it. Returning nil
+// uses the logger set with WithLogger.
+//
+// Example:
+//
+//	retry.NewRetry(retry.WithPerAttemptLogger(func(attempt int) retry.Logger {
+//	    if attempt >= 3 {
+//	        return alertLogger
+//	    }
+//	    return nil
+//	}))
+func WithPerAttemptLogger(fn func(attempt int) Logger) Option {
+	return func(rc *RetryConfig) {
+		rc.attemptLogger = fn
+	}
+}
+
+// WithZeroValueTreatedAsError treats a result that is the zero value of its
+// type, such as a nil pointer, 0 or "", as a failure when it comes with a
+// nil error. The attempt then fails with retryErr, which is retried like
+// any other error unless it is marked with NonRetryable. A nil retryErr
+// disables the check. Operations without a result, such as those of DoVoid,
+// WaitForPort or RetrySession.Do, are not affected.
+//
+// Example:
+//
+//	errNotFound := errors.New("user not found yet")
+//	rc := retry.NewRetry(retry.WithZeroValueTreatedAsError(errNotFound))
+//	user, err := retry.Do(ctx, rc, func() (*User, error) {
+//	    return cache.Get(id) // nil until the user is replicated
+//	})
+func WithZeroValueTreatedAsError(retryErr error) Option {
+	return func(rc *RetryConfig) {
+		rc.zeroValueErr = retryErr
+	}
+}
+
+// WithMultiError makes the *ExhaustedError returned when all attempts have
+// failed unwrap to the errors of all attempts, joined with errors.Join,
+// instead of only the last one. errors.Is and errors.As then find errors of
+// earlier attempts too, and the message lists every error.
+//
+// Example:
+//
+//	rc := retry.NewRetry(retry.WithMultiError())
+//	_, err := retry.Do(ctx, rc, fn)
+//	if errors.Is(err, ErrUnauthorized) {
+//	    // some attempt, not necessarily the last one, was rejected
+//	}
+func WithMultiError() Option {
+	return func(rc *RetryConfig) {
+		rc.multiError = true
+	}
+}
+
+// WithAbortOnContextValues stops retrying when the context passed to Do
+// carries a truthy value, i.e. one that is neither nil nor the zero value of
+// its type, under any of keys. This lets middleware suppress retries for a
+// single request. The context is checked before every retry; once a key is
+// found, Do returns an error wrapping the error of the last attempt. The
+// first attempt is always made.
+//
+// Example:
+//
+//	type noRetryKey struct{}
+//
+//	rc := retry.NewRetry(retry.WithAbortOnContextValues(noRetryKey{}))
+//	ctx = context.WithValue(ctx, noRetryKey{}, true)
+//	result, err := retry.Do(ctx, rc, fn) // a single attempt
+func WithAbortOnContextValues(keys ...any) Option {
+	return func(rc *RetryConfig) {
+		rc.abortKeys = keys
+	}
+}
+
+// WithMaxElapsedTime limits the total wall time of a Do call, including
+// the attempts and the delays between them, regardless of the remaining
+// attempts. Before each delay Do checks whether the next attempt could still
+// start within d; if not, it stops right away instead of sleeping and
+// returns an error wrapping both ErrMaxElapsedTimeExceeded and the error of
+// the last attempt. A running attempt is not interrupted; combine with
+// context.WithTimeout or WithMaxAttemptDuration for that. 0 disables the
+// limit.
+//
+// Example:
+//
+//	// Retry for at most 2 minutes.
+//	retry.NewRetry(retry.WithAttempts(100), retry.WithMaxElapsedTime(2*time.Minute))
+func WithMaxElapsedTime(d time.Duration) Option {
+	return func(rc *RetryConfig) {
+		rc.maxElapsed = d
+	}
+}
+
+// WithLogConfigOnStart enables logging of the effective retry configuration
+// at the start of every Do call. The configuration is written to the
+// configured logger using RetryConfig.String(), which makes it easy to
+// verify which attempts, delays and strategy are actually in use.
+//
+// Example:
+//
+//	retry.NewRetry(
+//	    retry.WithLogger(logger),
+//	    retry.WithLogConfigOnStart(true),
+//	)
+func WithLogConfigOnStart(enabled bool) Option {
+	return func(rc *RetryConfig) {
+		rc.logConfigOnStart = enabled
+	}
+}
+
 // FixedDelay returns a DelayTypeFunc that uses a constant delay between
 // retry attempts. The delay remains the same regardless of attempt number,
 // providing predictable and consistent retry timing.
@@ -132,6 +758,12 @@ func ExpBackoffWithJitter() DelayTypeFunc {
 			shift = 0
 		}
 
+		// Compare before multiplying: with many attempts, e.g. with
+		// WithUnlimitedAttempts, the product would overflow.
+		if baseDelay > 0 && (shift >= 63 || baseDelay > maxDelay>>shift) {
+			return maxDelay
+		}
+
 		expBackoff := baseDelay * time.Duration(1<<shift)
 
 		jitterMax := expBackoff / 5
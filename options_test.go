@@ -1,7 +1,16 @@
 package retry
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 )
@@ -74,6 +83,40 @@ func TestWithOnRetry(t *testing.T) {
 	}
 }
 
+// TestDoOnRetryOrder verifies that the hook runs after every failed attempt
+// except the last one, before the delay, with the upcoming delay.
+func TestDoOnRetryOrder(t *testing.T) {
+	t.Parallel()
+	errFail := errors.New("fail")
+
+	var events []string
+	rc := NewRetry(
+		WithAttempts(3),
+		WithDelay(time.Millisecond),
+		WithOnRetry(func(attempt int, err error, delay time.Duration) {
+			if !errors.Is(err, errFail) {
+				t.Errorf("expected the attempt error, got %v", err)
+			}
+			events = append(events, fmt.Sprintf("hook %d %v", attempt, delay))
+		}),
+		WithSleepFunc(func(d time.Duration) {
+			events = append(events, fmt.Sprintf("sleep %v", d))
+		}),
+	)
+
+	calls := 0
+	_, _ = Do(context.Background(), rc, func() (int, error) {
+		calls++
+		events = append(events, fmt.Sprintf("attempt %d", calls))
+		return 0, errFail
+	})
+
+	expected := []string{"attempt 1", "hook 1 1ms", "sleep 1ms", "attempt 2", "hook 2 1ms", "sleep 1ms", "attempt 3"}
+	if !slices.Equal(events, expected) {
+		t.Errorf("expected events %v, got %v", expected, events)
+	}
+}
+
 // TestFixedDelay verifies that FixedDelay function returns a DelayTypeFunc
 // that always returns the base delay regardless of attempt number.
 func TestFixedDelay(t *testing.T) {
@@ -168,3 +211,378 @@ func TestExpBackoffWithJitter_EdgeCases(t *testing.T) {
 		})
 	}
 }
+
+// TestExpBackoffWithJitterLargeAttempts verifies that the delay stays at
+// maxDelay for attempt numbers whose exponential factor would overflow.
+func TestExpBackoffWithJitterLargeAttempts(t *testing.T) {
+	t.Parallel()
+	delayFunc := ExpBackoffWithJitter()
+	baseDelay := 100 * time.Millisecond
+	maxDelay := time.Minute
+
+	for _, attempt := range []int{40, 64, 1000} {
+		if got := delayFunc(attempt, baseDelay, maxDelay); got != maxDelay {
+			t.Errorf("attempt %d: expected %v, got %v", attempt, maxDelay, got)
+		}
+	}
+}
+
+// TestWithUnlimitedAttempts verifies that Do keeps retrying until success,
+// a non-retryable error or the end of the context.
+func TestWithUnlimitedAttempts(t *testing.T) {
+	t.Parallel()
+	errFail := errors.New("fail")
+	rc := NewRetry(WithAttempts(2), WithUnlimitedAttempts(), WithDelay(time.Microsecond))
+
+	if got := rc.String(); !strings.HasPrefix(got, "attempts=unlimited ") {
+		t.Errorf("expected unlimited attempts in %q", got)
+	}
+	if err := rc.validate(); err != nil {
+		t.Errorf("expected a valid config, got %v", err)
+	}
+	if got := EstimatedTotalTime(rc); got != math.MaxInt64 {
+		t.Errorf("expected the maximum duration as estimate, got %v", got)
+	}
+
+	t.Run("until success", func(t *testing.T) {
+		t.Parallel()
+		calls := 0
+		result, err := Do(context.Background(), rc, func() (int, error) {
+			calls++
+			if calls < 50 {
+				return 0, errFail
+			}
+			return calls, nil
+		})
+		if err != nil || result != 50 {
+			t.Errorf("expected success after 50 calls, got %d, %v", result, err)
+		}
+	})
+
+	t.Run("until non-retryable", func(t *testing.T) {
+		t.Parallel()
+		calls := 0
+		_, err := Do(context.Background(), rc, func() (int, error) {
+			calls++
+			if calls < 10 {
+				return 0, errFail
+			}
+			return 0, NonRetryable(errFail)
+		})
+		if err == nil || IsExhausted(err) || calls != 10 {
+			t.Errorf("expected a non-retryable error after 10 calls, got %v after %d calls", err, calls)
+		}
+	})
+
+	t.Run("until canceled", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := Do(ctx, rc, func() (int, error) {
+			return 0, errFail
+		})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected the context error, got %v", err)
+		}
+	})
+}
+
+// TestWithRetryIf verifies that WithRetryIf sets the predicate and that it
+// is combined with NonRetryable detection.
+func TestWithRetryIf(t *testing.T) {
+	target := errors.New("retry me")
+	r := NewRetry(WithRetryIf(func(err error) bool { return errors.Is(err, target) }))
+
+	if !r.shouldRetry(target) {
+		t.Error("expected matching error to be retryable")
+	}
+
+	if r.shouldRetry(errors.New("other")) {
+		t.Error("expected non-matching error to not be retryable")
+	}
+
+	if r.shouldRetry(NonRetryable(target)) {
+		t.Error("expected NonRetryable error to not be retryable")
+	}
+}
+
+// TestDoWithRetryIf verifies that Do retries only the errors accepted by
+// the predicate and returns a rejected error right away.
+func TestDoWithRetryIf(t *testing.T) {
+	t.Parallel()
+	errUnavailable := errors.New("503 service unavailable")
+	errNotFound := errors.New("404 not found")
+
+	testCases := []struct {
+		name          string
+		err           error
+		expectedCalls int
+	}{
+		{name: "accepted error is retried", err: errUnavailable, expectedCalls: 3},
+		{name: "wrapped accepted error is retried", err: fmt.Errorf("fetch: %w", errUnavailable), expectedCalls: 3},
+		{name: "rejected error is returned", err: errNotFound, expectedCalls: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rc := NewRetry(
+				WithDelay(time.Millisecond),
+				WithRetryIf(func(err error) bool { return errors.Is(err, errUnavailable) }),
+			)
+
+			calls := 0
+			_, err := Do(context.Background(), rc, func() (int, error) {
+				calls++
+				return 0, tc.err
+			})
+
+			if !errors.Is(err, tc.err) {
+				t.Errorf("expected %v, got %v", tc.err, err)
+			}
+			if calls != tc.expectedCalls {
+				t.Errorf("expected %d calls, got %d", tc.expectedCalls, calls)
+			}
+		})
+	}
+}
+
+// TestWithTimeBasedAttempts verifies that the attempt count is derived from
+// the budget and window.
+func TestWithTimeBasedAttempts(t *testing.T) {
+	r := NewRetry(WithTimeBasedAttempts(2*time.Second, time.Minute))
+
+	if r.attempts != 30 {
+		t.Errorf("expected 30 attempts, got %d", r.attempts)
+	}
+
+	if r.attemptWindow != 2*time.Second {
+		t.Errorf("expected attemptWindow to be 2s, got %v", r.attemptWindow)
+	}
+
+	if r := NewRetry(WithTimeBasedAttempts(time.Minute, time.Second)); r.attempts != 1 {
+		t.Errorf("expected at least 1 attempt, got %d", r.attempts)
+	}
+}
+
+// TestWithMinDelay verifies that delays below the minimum are clamped while
+// longer delays are left unchanged.
+func TestWithMinDelay(t *testing.T) {
+	r := NewRetry(WithDelay(0), WithMinDelay(50*time.Millisecond), WithDelayType(FixedDelay()))
+
+	if d := r.nextDelay(1); d != 50*time.Millisecond {
+		t.Errorf("expected delay to be clamped to 50ms, got %v", d)
+	}
+
+	r = NewRetry(WithDelay(time.Second), WithMinDelay(50*time.Millisecond))
+	if d := r.nextDelay(1); d != time.Second {
+		t.Errorf("expected delay to stay 1s, got %v", d)
+	}
+}
+
+// TestWithDelayCap verifies that the per-attempt cap overrides the global
+// maximum delay.
+func TestWithDelayCap(t *testing.T) {
+	t.Parallel()
+	doubling := func(attempt int, baseDelay, maxDelay time.Duration) time.Duration {
+		return min(baseDelay<<(attempt-1), maxDelay)
+	}
+	r := NewRetry(
+		WithDelay(time.Second),
+		WithMaxDelay(time.Minute),
+		WithDelayType(doubling),
+		WithDelayCap(func(attempt int) time.Duration {
+			if attempt <= 3 {
+				return 3 * time.Second
+			}
+			return 30 * time.Second
+		}),
+	)
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second}
+	for i, want := range expected {
+		if got := r.nextDelay(i + 1); got != want {
+			t.Errorf("attempt %d: expected %v, got %v", i+1, want, got)
+		}
+	}
+
+	fixed := NewRetry(WithDelay(time.Second), WithDelayCap(func(int) time.Duration { return 100 * time.Millisecond }))
+	if got := fixed.nextDelay(1); got != 100*time.Millisecond {
+		t.Errorf("expected strategies ignoring the maximum to be capped at 100ms, got %v", got)
+	}
+}
+
+// zeroThenValue runs Do with WithZeroValueTreatedAsError on a function that
+// returns the zero value of T once and then value, and checks the result.
+func zeroThenValue[T comparable](t *testing.T, value T) {
+	t.Helper()
+	errEmpty := errors.New("empty result")
+	rc := NewRetry(WithAttempts(3), WithDelay(time.Millisecond), WithZeroValueTreatedAsError(errEmpty))
+
+	calls := 0
+	result, err := Do(context.Background(), rc, func() (T, error) {
+		calls++
+		if calls == 1 {
+			var zero T
+			return zero, nil
+		}
+		return value, nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if calls != 2 || result != value {
+		t.Errorf("expected %v after 2 calls, got %v after %d calls", value, result, calls)
+	}
+
+	_, err = Do(context.Background(), rc, func() (T, error) {
+		var zero T
+		return zero, nil
+	})
+	if !errors.Is(err, errEmpty) {
+		t.Errorf("expected exhaustion with the configured error, got %v", err)
+	}
+}
+
+// TestWithZeroValueTreatedAsError verifies that zero results of various
+// types are retried and that non-zero results are returned.
+func TestWithZeroValueTreatedAsError(t *testing.T) {
+	t.Parallel()
+	type user struct{ name string }
+
+	t.Run("pointer to struct", func(t *testing.T) {
+		t.Parallel()
+		zeroThenValue(t, &user{name: "gopher"})
+	})
+	t.Run("int", func(t *testing.T) {
+		t.Parallel()
+		zeroThenValue(t, 42)
+	})
+	t.Run("string", func(t *testing.T) {
+		t.Parallel()
+		zeroThenValue(t, "ready")
+	})
+	t.Run("interface", func(t *testing.T) {
+		t.Parallel()
+		zeroThenValue[fmt.Stringer](t, time.Second)
+	})
+}
+
+// TestWithZeroValueTreatedAsErrorVoid verifies that the helpers for
+// operations without a result are not affected by the zero value check.
+func TestWithZeroValueTreatedAsErrorVoid(t *testing.T) {
+	t.Parallel()
+	opts := []Option{WithAttempts(2), WithDelay(time.Millisecond), WithZeroValueTreatedAsError(errors.New("zero"))}
+	ctx := context.Background()
+
+	testCases := []struct {
+		name string
+		run  func(rc *RetryConfig) error
+	}{
+		{name: "DoVoid", run: func(rc *RetryConfig) error {
+			return DoVoid(ctx, rc, func() error { return nil })
+		}},
+		{name: "MustDoVoid", run: func(*RetryConfig) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic: %v", r)
+				}
+			}()
+			MustDoVoid(ctx, func() error { return nil }, opts...)
+			return nil
+		}},
+		{name: "WrapContext", run: func(rc *RetryConfig) error {
+			return WrapContext(rc, func(context.Context) error { return nil })(ctx)
+		}},
+		{name: "Group.Go", run: func(rc *RetryConfig) error {
+			g := NewGroup(ctx, rc)
+			g.Go(func(context.Context) error { return nil })
+			return g.Wait()
+		}},
+		{name: "WaitForPort", run: func(rc *RetryConfig) error {
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				return err
+			}
+			defer l.Close()
+			return WaitForPort(ctx, "127.0.0.1", l.Addr().(*net.TCPAddr).Port, rc)
+		}},
+		{name: "WaitForHTTP", run: func(rc *RetryConfig) error {
+			srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+			defer srv.Close()
+			return WaitForHTTP(ctx, srv.URL, http.StatusOK, rc)
+		}},
+		{name: "RetryConn.Write", run: func(rc *RetryConfig) error {
+			conn := RetryConn(ctx, func() (net.Conn, error) { return &brokenConn{limit: 10}, nil }, rc)
+			_, err := conn.Write([]byte("x"))
+			return err
+		}},
+		{name: "RetrySession.Do", run: func(rc *RetryConfig) error {
+			return NewRetrySession(rc).Do(ctx, func() error { return nil })
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tc.run(NewRetry(opts...)); err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+// TestWithFixedAttemptDelay verifies that the delays of the schedule are
+// used in order and that the last one is repeated.
+func TestWithFixedAttemptDelay(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		attempts int
+		schedule []time.Duration
+		expected []time.Duration
+	}{
+		{
+			name:     "shorter than attempts",
+			attempts: 5,
+			schedule: []time.Duration{0, time.Millisecond, 3 * time.Millisecond},
+			expected: []time.Duration{0, time.Millisecond, 3 * time.Millisecond, 3 * time.Millisecond},
+		},
+		{
+			name:     "longer than attempts",
+			attempts: 3,
+			schedule: []time.Duration{2 * time.Millisecond, time.Millisecond, 5 * time.Millisecond},
+			expected: []time.Duration{2 * time.Millisecond, time.Millisecond},
+		},
+		{
+			name:     "empty keeps the strategy",
+			attempts: 3,
+			expected: []time.Duration{time.Millisecond, time.Millisecond},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var delays []time.Duration
+			rc := NewRetry(
+				WithAttempts(tc.attempts),
+				WithDelay(time.Millisecond),
+				WithFixedAttemptDelay(tc.schedule),
+				WithOnRetry(func(_ int, _ error, delay time.Duration) {
+					delays = append(delays, delay)
+				}),
+			)
+
+			_, _ = Do(context.Background(), rc, func() (int, error) {
+				return 0, errors.New("fail")
+			})
+
+			if !slices.Equal(delays, tc.expected) {
+				t.Errorf("expected delays %v, got %v", tc.expected, delays)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package retry
+
+import "fmt"
+
+// ProgressFormat describes the progress indicator written by
+// WithProgressWriter.
+type ProgressFormat struct {
+	// SpinnerChars are shown one after another in front of the line, one
+	// per attempt. An empty string disables the spinner.
+	SpinnerChars string
+
+	// LineTemplate is formatted with the current attempt and the maximum
+	// number of attempts, in this order, e.g. "Attempt %d/%d".
+	LineTemplate string
+}
+
+var (
+	// ProgressFormatDefault shows a braille spinner followed by
+	// "Attempt 2/5".
+	ProgressFormatDefault = ProgressFormat{
+		SpinnerChars: "⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏",
+		LineTemplate: "Attempt %d/%d",
+	}
+
+	// ProgressFormatMinimal shows only "2/5" without a spinner.
+	ProgressFormatMinimal = ProgressFormat{
+		LineTemplate: "%d/%d",
+	}
+)
+
+// clearLine moves the cursor to the start of the line and erases it.
+const clearLine = "\r\033[K"
+
+// writeProgress overwrites the current terminal line with the progress of
+// the given attempt, if a progress writer is configured.
+func (rc *RetryConfig) writeProgress(attempt, attempts int) {
+	if rc.progress == nil {
+		return
+	}
+
+	if attempts == unlimitedAttempts {
+		attempts = 0
+	}
+
+	line := fmt.Sprintf(rc.progressFormat.LineTemplate, attempt, attempts)
+	if spinner := []rune(rc.progressFormat.SpinnerChars); len(spinner) > 0 {
+		line = string(spinner[(attempt-1)%len(spinner)]) + " " + line
+	}
+
+	fmt.Fprint(rc.progress, clearLine+line)
+}
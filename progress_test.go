@@ -0,0 +1,52 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWithProgressWriter verifies that every attempt overwrites the line
+// and that the output ends with a newline.
+func TestWithProgressWriter(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		format   ProgressFormat
+		expected string
+	}{
+		{
+			name:     "default",
+			format:   ProgressFormatDefault,
+			expected: "\r\033[K⠋ Attempt 1/3\r\033[K⠙ Attempt 2/3\r\033[K⠹ Attempt 3/3\n",
+		},
+		{
+			name:     "minimal",
+			format:   ProgressFormatMinimal,
+			expected: "\r\033[K1/3\r\033[K2/3\r\033[K3/3\n",
+		},
+		{
+			name:     "custom spinner wraps around",
+			format:   ProgressFormat{SpinnerChars: "-|", LineTemplate: "try %d of %d"},
+			expected: "\r\033[K- try 1 of 3\r\033[K| try 2 of 3\r\033[K- try 3 of 3\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var out strings.Builder
+			rc := NewRetry(WithAttempts(3), WithDelay(time.Millisecond), WithProgressWriter(&out, tc.format))
+
+			_, _ = Do(context.Background(), rc, func() (int, error) {
+				return 0, errors.New("fail")
+			})
+
+			if out.String() != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrQuorumNotReached is returned by DoQuorum when fewer than k functions
+// succeeded before exhausting their retry budgets.
+var ErrQuorumNotReached = errors.New("quorum not reached")
+
+// DoQuorum retries all fns concurrently, each with its own retry budget
+// configured by opts, and returns as soon as k of them have succeeded. The
+// results are returned in the order the functions succeeded. The remaining
+// retry loops are canceled; functions that are already running are not
+// interrupted, as they do not receive the context, and their results are
+// discarded.
+//
+// If so many functions fail that k successes are no longer possible, or the
+// context is canceled, DoQuorum returns an error wrapping ErrQuorumNotReached
+// together with the errors of the failed functions.
+//
+// Example:
+//
+//	// Write to 3 replicas and succeed once a majority acknowledged.
+//	acks, err := retry.DoQuorum(ctx, 2, []func() (Ack, error){
+//	    writeReplica1, writeReplica2, writeReplica3,
+//	}, retry.WithAttempts(5))
+func DoQuorum[T any](ctx context.Context, k int, fns []func() (T, error), opts ...Option) ([]T, error) {
+	if k <= 0 || k > len(fns) {
+		return nil, fmt.Errorf("%w: quorum must be between 1 and %d, got %d", ErrInvalidConfig, len(fns), k)
+	}
+
+	rc := NewRetry(opts...)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		data T
+		err  error
+	}
+
+	// Buffered so that loops finishing after DoQuorum returned do not block.
+	results := make(chan result, len(fns))
+	for _, fn := range fns {
+		go func() {
+			data, err := Do(ctx, rc, RetryFunc[T](fn))
+			results <- result{data: data, err: err}
+		}()
+	}
+
+	var succeeded []T
+	var errs []error
+	for range fns {
+		res := <-results
+		if res.err != nil {
+			errs = append(errs, res.err)
+			if len(fns)-len(errs) < k {
+				break
+			}
+			continue
+		}
+
+		succeeded = append(succeeded, res.data)
+		if len(succeeded) == k {
+			return succeeded, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %d of %d required successes: %w",
+		ErrQuorumNotReached, len(succeeded), k, errors.Join(errs...))
+}
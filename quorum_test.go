@@ -0,0 +1,81 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestDoQuorumReached verifies that DoQuorum returns once k functions have
+// succeeded and cancels the retry loops of the others.
+func TestDoQuorumReached(t *testing.T) {
+	t.Parallel()
+	failing := make(chan struct{}, 10)
+
+	fns := []func() (int, error){
+		func() (int, error) { return 1, nil },
+		func() (int, error) {
+			failing <- struct{}{}
+			return 0, errors.New("replica down")
+		},
+		func() (int, error) { return 3, nil },
+	}
+
+	start := time.Now()
+	results, err := DoQuorum(context.Background(), 2, fns, WithAttempts(100), WithDelay(time.Second))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected DoQuorum to return without waiting for the failing loop, took %v", elapsed)
+	}
+
+	slices.Sort(results)
+	if !slices.Equal(results, []int{1, 3}) {
+		t.Errorf("expected results [1 3], got %v", results)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if n := len(failing); n > 1 {
+		t.Errorf("expected failing loop to be canceled, got %d attempts", n)
+	}
+}
+
+// TestDoQuorumNotReached verifies that DoQuorum fails as soon as the quorum
+// cannot be reached anymore and reports the underlying errors.
+func TestDoQuorumNotReached(t *testing.T) {
+	t.Parallel()
+	errDown := errors.New("replica down")
+
+	fns := []func() (int, error){
+		func() (int, error) { return 1, nil },
+		func() (int, error) { return 0, errDown },
+		func() (int, error) { return 0, errDown },
+	}
+
+	results, err := DoQuorum(context.Background(), 2, fns, WithAttempts(2), WithDelay(time.Millisecond))
+	if !errors.Is(err, ErrQuorumNotReached) {
+		t.Fatalf("expected ErrQuorumNotReached, got %v", err)
+	}
+
+	if !errors.Is(err, errDown) {
+		t.Errorf("expected underlying error to be reachable, got %v", err)
+	}
+
+	if results != nil {
+		t.Errorf("expected no results, got %v", results)
+	}
+}
+
+// TestDoQuorumInvalid verifies that an impossible quorum is rejected.
+func TestDoQuorumInvalid(t *testing.T) {
+	t.Parallel()
+	fns := []func() (int, error){func() (int, error) { return 1, nil }}
+
+	if _, err := DoQuorum(context.Background(), 2, fns); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig, got %v", err)
+	}
+}
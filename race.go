@@ -0,0 +1,76 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// RaceResult identifies the winner of DoRaceResult.
+type RaceResult[T any] struct {
+	Value   T   // Result of the winning function
+	Index   int // Index of the winning function in fns
+	Attempt int // Attempt of the winning function that succeeded
+}
+
+// DoRace starts one retry loop per function, all configured by rc, and
+// returns the result of the first one that succeeds. The other loops are
+// canceled; functions that are already running are not interrupted, as they
+// do not receive the context, and their results are discarded. Every loop
+// goroutine exits once its current attempt has returned.
+//
+// If all loops fail, DoRace returns an error joining the errors of all
+// loops, in the order of fns.
+//
+// Example:
+//
+//	// Query all mirrors and use whichever answers first.
+//	data, err := retry.DoRace(ctx, []func() ([]byte, error){
+//	    fetchMirror1, fetchMirror2, fetchMirror3,
+//	}, retry.NewRetry(retry.WithAttempts(3)))
+func DoRace[T any](ctx context.Context, fns []func() (T, error), rc *RetryConfig) (T, error) {
+	res, err := DoRaceResult(ctx, fns, rc)
+	return res.Value, err
+}
+
+// DoRaceResult works like DoRace, but also reports which function won and
+// on which attempt.
+//
+// Example:
+//
+//	res, err := retry.DoRaceResult(ctx, mirrors, rc)
+//	log.Printf("mirror %d answered on attempt %d", res.Index, res.Attempt)
+func DoRaceResult[T any](ctx context.Context, fns []func() (T, error), rc *RetryConfig) (RaceResult[T], error) {
+	if len(fns) == 0 {
+		return RaceResult[T]{}, fmt.Errorf("%w: no functions to race", ErrInvalidConfig)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		RaceResult[T]
+		err error
+	}
+
+	// Buffered so that loops finishing after DoRaceResult returned do not block.
+	results := make(chan result, len(fns))
+	for i, fn := range fns {
+		go func() {
+			stats := &RetryStats{}
+			data, err := do(ctx, rc, fn, loopState{observer: stats})
+			results <- result{RaceResult: RaceResult[T]{Value: data, Index: i, Attempt: stats.Attempts}, err: err}
+		}()
+	}
+
+	errs := make([]error, len(fns))
+	for range fns {
+		res := <-results
+		if res.err == nil {
+			return res.RaceResult, nil
+		}
+		errs[res.Index] = fmt.Errorf("function %d: %w", res.Index, res.err)
+	}
+
+	return RaceResult[T]{}, fmt.Errorf("all %d functions failed: %w", len(fns), errors.Join(errs...))
+}
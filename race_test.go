@@ -0,0 +1,79 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestDoRaceResult verifies that the first successful loop wins, that its
+// index and attempt are reported and that the other loops are canceled.
+func TestDoRaceResult(t *testing.T) {
+	t.Parallel()
+	var slowCalls atomic.Int32
+	calls := 0
+
+	fns := []func() (string, error){
+		func() (string, error) {
+			slowCalls.Add(1)
+			return "", errors.New("mirror down")
+		},
+		func() (string, error) {
+			calls++
+			if calls < 2 {
+				return "", errors.New("busy")
+			}
+			return "mirror 1", nil
+		},
+	}
+
+	rc := NewRetry(WithAttempts(100), WithDelay(10*time.Millisecond))
+	res, err := DoRaceResult(context.Background(), fns, rc)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if res.Value != "mirror 1" || res.Index != 1 || res.Attempt != 2 {
+		t.Errorf("expected mirror 1 to win on attempt 2, got %+v", res)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	n := slowCalls.Load()
+	time.Sleep(50 * time.Millisecond)
+	if slowCalls.Load() != n {
+		t.Errorf("expected the losing loop to be canceled, attempts grew from %d to %d", n, slowCalls.Load())
+	}
+}
+
+// TestDoRaceAllFail verifies that DoRace joins the errors of all loops and
+// leaves no goroutines behind.
+func TestDoRaceAllFail(t *testing.T) {
+	errA := errors.New("a")
+	errB := errors.New("b")
+	before := runtime.NumGoroutine()
+
+	_, err := DoRace(context.Background(), []func() (int, error){
+		func() (int, error) { return 0, errA },
+		func() (int, error) { return 0, errB },
+	}, NewRetry(WithAttempts(2), WithDelay(time.Millisecond)))
+
+	if !errors.Is(err, errA) || !errors.Is(err, errB) || !errors.Is(err, ErrExhausted) {
+		t.Errorf("expected the errors of both loops, got %v", err)
+	}
+
+	// Loop goroutines may still be returning after sending their result.
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("expected no leaked goroutines, had %d before and %d after", before, after)
+	}
+
+	if _, err := DoRace[int](context.Background(), nil, NewRetry()); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig without functions, got %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrPolicyNotFound is returned by PolicyRegistry.Get for names that were
+// not registered.
+var ErrPolicyNotFound = errors.New("retry policy not found")
+
+// PolicyName names a retry policy for WrapWithBackoff: one of the built-in
+// policies such as PolicyStandard, or a policy registered in
+// DefaultRegistry.
+type PolicyName string
+
+// Built-in policies.
+const (
+	// PolicyStandard makes 3 attempts with exponential backoff and jitter,
+	// starting at 100ms and capped at 1s.
+	PolicyStandard PolicyName = "standard"
+)
+
+// builtinPolicies creates the configs of the built-in policies.
+var builtinPolicies = map[PolicyName]func() *RetryConfig{
+	PolicyStandard: func() *RetryConfig {
+		return NewRetry(WithDelayType(ExpBackoffWithJitter()))
+	},
+}
+
+// lookup returns the config of the built-in policy name or, failing that,
+// the one registered under name in DefaultRegistry.
+func (name PolicyName) lookup() (*RetryConfig, error) {
+	if policy, ok := builtinPolicies[name]; ok {
+		return policy(), nil
+	}
+
+	return DefaultRegistry.Get(string(name))
+}
+
+// DefaultRegistry is the package-level registry for retry policies shared
+// across a service.
+var DefaultRegistry = NewPolicyRegistry()
+
+// PolicyRegistry stores retry configurations under names, so that policies
+// can be defined centrally and referenced by name, e.g. per downstream
+// service. It is safe for concurrent use. Use NewPolicyRegistry() to create
+// instances or the package-level DefaultRegistry.
+type PolicyRegistry struct {
+	mu       sync.RWMutex
+	policies map[string]*RetryConfig
+}
+
+// NewPolicyRegistry creates an empty PolicyRegistry.
+func NewPolicyRegistry() *PolicyRegistry {
+	return &PolicyRegistry{policies: map[string]*RetryConfig{}}
+}
+
+// Register stores rc under name. It fails if name is empty, rc is nil, or
+// a policy with the same name is already registered.
+//
+// Example:
+//
+//	func init() {
+//	    retry.DefaultRegistry.Register("payment-service-read", retry.NewRetry(
+//	        retry.WithAttempts(5),
+//	        retry.WithDelayType(retry.ExpBackoffWithJitter()),
+//	    ))
+//	}
+func (r *PolicyRegistry) Register(name string, rc *RetryConfig) error {
+	if name == "" {
+		return fmt.Errorf("%w: policy name must not be empty", ErrInvalidConfig)
+	}
+	if rc == nil {
+		return fmt.Errorf("%w: policy %q must not be nil", ErrInvalidConfig, name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.policies[name]; ok {
+		return fmt.Errorf("retry policy %q already registered", name)
+	}
+	r.policies[name] = rc
+
+	return nil
+}
+
+// Get returns the policy registered under name. The returned error wraps
+// ErrPolicyNotFound if there is none.
+//
+// Example:
+//
+//	rc, err := retry.DefaultRegistry.Get("payment-service-read")
+//	if err != nil {
+//	    return err
+//	}
+//	result, err := retry.Do(ctx, rc, fetchPayment)
+func (r *PolicyRegistry) Get(name string) (*RetryConfig, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rc, ok := r.policies[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrPolicyNotFound, name)
+	}
+
+	return rc, nil
+}
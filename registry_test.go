@@ -0,0 +1,64 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// TestPolicyRegistry verifies registration, lookup and the error cases.
+func TestPolicyRegistry(t *testing.T) {
+	t.Parallel()
+	r := NewPolicyRegistry()
+	rc := NewRetry(WithAttempts(5))
+
+	if err := r.Register("payments", rc); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got, err := r.Get("payments"); err != nil || got != rc {
+		t.Errorf("expected the registered config, got %v, %v", got, err)
+	}
+
+	if err := r.Register("payments", NewRetry()); err == nil {
+		t.Error("expected duplicate registration to fail")
+	}
+	if err := r.Register("", rc); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for an empty name, got %v", err)
+	}
+	if err := r.Register("nil", nil); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for a nil config, got %v", err)
+	}
+	if _, err := r.Get("unknown"); !errors.Is(err, ErrPolicyNotFound) {
+		t.Errorf("expected ErrPolicyNotFound, got %v", err)
+	}
+}
+
+// TestPolicyRegistryConcurrent verifies that concurrent registration and
+// lookup are safe; run with -race.
+func TestPolicyRegistryConcurrent(t *testing.T) {
+	t.Parallel()
+	r := NewPolicyRegistry()
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := r.Register(fmt.Sprintf("policy-%d", i), NewRetry()); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = r.Get(fmt.Sprintf("policy-%d", i))
+		}()
+	}
+	wg.Wait()
+
+	for i := range 50 {
+		if _, err := r.Get(fmt.Sprintf("policy-%d", i)); err != nil {
+			t.Errorf("expected policy-%d to be registered, got %v", i, err)
+		}
+	}
+}
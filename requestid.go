@@ -0,0 +1,59 @@
+package retry
+
+import (
+	"log/slog"
+
+	"github.com/1amDudman/try-again-go/internal/uuid"
+)
+
+// WithRequestID attaches a request ID to the retry configuration so that
+// all attempts of a logical operation can be correlated in distributed
+// traces and logs. Every message written to the Logger is prefixed with
+// "[request_id=<id>] ", slog records get a request_id attribute, and hooks
+// can read the ID with RetryConfig.RequestID. If id is empty, a random UUID
+// is generated for every configuration the option is applied to.
+//
+// The ID belongs to the configuration, so create a configuration per
+// logical operation when IDs should differ.
+//
+// Example:
+//
+//	rc := retry.NewRetry(
+//	    retry.WithRequestID(r.Header.Get("X-Request-ID")),
+//	    retry.WithLogger(log.Default()),
+//	)
+func WithRequestID(id string) Option {
+	return func(rc *RetryConfig) {
+		rid := id
+		if rid == "" {
+			rid = uuid.New()
+		}
+		rc.requestID = rid
+	}
+}
+
+// RequestID returns the request ID set with WithRequestID, or an empty
+// string if there is none.
+func (rc *RetryConfig) RequestID() string {
+	return rc.requestID
+}
+
+// requestIDLogger prefixes every message of the wrapped logger with the
+// request ID.
+type requestIDLogger struct {
+	id     string
+	logger Logger
+}
+
+func (l requestIDLogger) Printf(format string, v ...any) {
+	l.logger.Printf("[request_id=%s] "+format, append([]any{l.id}, v...)...)
+}
+
+// requestIDAttrs returns the slog attributes identifying the request, if
+// any.
+func (rc *RetryConfig) requestIDAttrs() []slog.Attr {
+	if rc.requestID == "" {
+		return nil
+	}
+	return []slog.Attr{slog.String("request_id", rc.requestID)}
+}
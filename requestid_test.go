@@ -0,0 +1,89 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWithRequestIDLogLines verifies that every log line of a Do call
+// carries the same request ID, regardless of the option order.
+func TestWithRequestIDLogLines(t *testing.T) {
+	t.Parallel()
+	logger := &recordingLogger{}
+	var buf bytes.Buffer
+	var hookIDs []string
+	var rc *RetryConfig
+	rc = NewRetry(
+		WithOnRetry(func(int, error, time.Duration) {
+			hookIDs = append(hookIDs, rc.RequestID())
+		}),
+		WithRequestID("req-42"),
+		WithLogger(logger),
+		WithSlog(slog.New(slog.NewTextHandler(&buf, nil))),
+		WithAttempts(3),
+		WithDelay(time.Millisecond),
+	)
+
+	_, _ = Do(context.Background(), rc, func() (int, error) {
+		return 0, errors.New("fail")
+	})
+
+	if len(logger.lines) == 0 {
+		t.Fatal("expected log lines")
+	}
+	for _, line := range logger.lines {
+		if !strings.HasPrefix(line, "[request_id=req-42] ") {
+			t.Errorf("expected line to carry the request ID, got %q", line)
+		}
+	}
+
+	records := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	for _, record := range records {
+		if !strings.Contains(record, "request_id=req-42") {
+			t.Errorf("expected slog record to carry the request ID, got %q", record)
+		}
+	}
+
+	if len(hookIDs) != 2 || hookIDs[0] != "req-42" || hookIDs[1] != "req-42" {
+		t.Errorf("expected hooks to see the request ID twice, got %v", hookIDs)
+	}
+}
+
+// TestWithRequestIDGenerated verifies that an empty ID is replaced with a
+// random UUID.
+func TestWithRequestIDGenerated(t *testing.T) {
+	t.Parallel()
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	first := NewRetry(WithRequestID("")).RequestID()
+	second := NewRetry(WithRequestID("")).RequestID()
+	if !uuid.MatchString(first) {
+		t.Errorf("expected a UUID, got %q", first)
+	}
+	if first == second {
+		t.Errorf("expected different IDs, got %q twice", first)
+	}
+
+	if id := NewRetry().RequestID(); id != "" {
+		t.Errorf("expected no request ID by default, got %q", id)
+	}
+}
+
+// TestWithRequestIDSharedOption verifies that one option with an empty ID
+// generates a new ID for every config it is applied to.
+func TestWithRequestIDSharedOption(t *testing.T) {
+	t.Parallel()
+	opt := WithRequestID("")
+
+	first := NewRetry(opt).RequestID()
+	second := NewRetry(opt).RequestID()
+	if first == "" || first == second {
+		t.Errorf("expected two different IDs, got %q and %q", first, second)
+	}
+}
@@ -3,6 +3,14 @@ package retry
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -20,6 +28,10 @@ type DelayTypeFunc func(attempt int, baseDelay, maxDelay time.Duration) time.Dur
 // executed after a failed attempt, right before the delay.
 type OnRetryFunc func(attempt int, err error, delay time.Duration)
 
+// RetryIfFunc defines a predicate that decides whether an error returned by
+// the retry function should trigger another attempt.
+type RetryIfFunc func(err error) bool
+
 // RetryConfig holds the complete configuration for retry behavior.
 // It encapsulates all retry parameters including attempts, delays, logging,
 // and delay calculation strategy. Use NewRetry() to create instances with
@@ -28,9 +40,215 @@ type RetryConfig struct {
 	attempts  int           // Number of retry attempts
 	baseDelay time.Duration // Base delay between attempts
 	maxDelay  time.Duration // Maximum delay cap
+	minDelay  time.Duration // Minimum delay floor
 	delayType DelayTypeFunc // Delay calculation strategy
 	logger    Logger        // Logger for retry events
-	onRetry   OnRetryFunc   // TODO
+	onRetry   OnRetryFunc   // Hook called after a failed attempt, before the delay
+	retryIf   RetryIfFunc   // Predicate deciding which errors are retried
+
+	logConfigOnStart bool // Log the effective configuration when Do starts
+
+	maxAttemptDuration time.Duration     // Upper bound on a single attempt, 0 means unbounded
+	abortFunc          func(attempt int) // Called when an attempt exceeds maxAttemptDuration
+
+	attemptWindow time.Duration // Fixed spacing between attempt starts, 0 means disabled
+
+	dynamicAttempts func() int                      // Resolves the attempt count at the start of each Do call
+	dynamicDelay    func(attempt int) time.Duration // Resolves the delay right before each sleep
+
+	errorWindowSize int                       // Number of recent errors passed to errorWindowFunc
+	errorWindowFunc func(errors []error) bool // Decides whether to keep retrying based on recent errors
+
+	allowFn   func(time.Time) bool // Reports whether retries may run at the given time
+	allowPoll time.Duration        // Polling interval while retries are not allowed
+
+	delayObserver func(planned, actual time.Duration) // Receives planned and measured sleep durations
+
+	slog *slog.Logger // Structured logger for retry events
+
+	recoverPanics bool                             // Convert panics of the retry function into errors
+	panicHandler  func(attempt int, recovered any) // Receives recovered panic values
+
+	contextTagger func(ctx context.Context, attempt int) context.Context // Derives the context of each attempt
+
+	progress       io.Writer      // Receives the live progress indicator, nil means disabled
+	progressFormat ProgressFormat // Layout of the progress indicator
+
+	attemptCost func(attempt int) float64 // Returns the cost of an attempt
+	costBudget  float64                   // Maximum cumulative cost, 0 means unlimited
+
+	telemetry TelemetryProvider // Receives spans, attempts and results
+
+	failFast bool // Stop when a failed first attempt took longer than baseDelay
+
+	maxConcurrent int // Maximum number of operations DoAll runs at once, 0 means unlimited
+
+	deadlineMargin time.Duration // Skip attempts when the context deadline is this close, 0 means disabled
+
+	sleepFn func(time.Duration) // Replaces the timer-based sleep between attempts, nil means default
+
+	requestID string // Correlates all attempts in logs and hooks
+
+	delayMiddleware []DelayMiddleware // Wrap delayType, the first one outermost
+
+	attemptFilter func(attempt int, err error) bool // Decides whether an upcoming attempt is made
+
+	delayCap func(attempt int) time.Duration // Per-attempt maximum delay, overrides maxDelay
+
+	mu *sync.Mutex // Serializes hooks and delay calculation across concurrent Do calls, nil means disabled
+
+	breaker           CircuitBreaker  // Guards every attempt, nil means disabled
+	breakerTransition *transitionHook // Registered on breaker by NewRetry
+
+	contextValues map[any]any // Added to the context of each attempt of DoWithContext
+
+	attemptLogger func(attempt int) Logger // Overrides logger per attempt, nil means disabled
+
+	zeroValueErr error // Returned instead of a zero result with a nil error, nil means disabled
+
+	multiError bool // Exhaustion errors unwrap to the errors of all attempts
+
+	stats *AtomicStats // Aggregates statistics of all Do calls, nil means disabled
+
+	abortKeys []any // Context keys whose truthy values stop retrying
+
+	budgetKey string // Key of the shared budget in GlobalBudgetRegistry, empty means unlimited
+
+	maxElapsed time.Duration // Upper bound on the wall time of Do, 0 means unbounded
+
+	clock Clock // Time source for measurements and delays
+}
+
+// String returns a single-line, human-readable description of the effective
+// retry configuration. It is primarily intended for logging and debugging.
+//
+// Example output:
+//
+//	attempts=3 baseDelay=100ms maxDelay=1s strategy=FixedDelay onRetry=false
+func (rc *RetryConfig) String() string {
+	return fmt.Sprintf("attempts=%s baseDelay=%v maxDelay=%v strategy=%s onRetry=%t",
+		formatAttempts(rc.attempts), rc.baseDelay, rc.maxDelay, funcName(rc.delayType), rc.onRetry != nil)
+}
+
+// PrintTo writes a verbose, human-readable description of the configuration
+// to w, one option per line. Unlike String, which returns a single line, it
+// lists every option and is intended for startup diagnostics.
+//
+// Example:
+//
+//	func init() {
+//	    retryConfig.PrintTo(os.Stderr)
+//	}
+func (rc *RetryConfig) PrintTo(w io.Writer) (n int, err error) {
+	for _, f := range rc.fields() {
+		written, err := fmt.Fprintf(w, "%-20s %s\n", f.name+":", f.value)
+		n += written
+		if err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+// WriteTo implements io.WriterTo using the same output as PrintTo.
+func (rc *RetryConfig) WriteTo(w io.Writer) (int64, error) {
+	n, err := rc.PrintTo(w)
+	return int64(n), err
+}
+
+// configField is a single named option value used by PrintTo.
+type configField struct {
+	name  string
+	value string
+}
+
+// formatAttempts formats a number of attempts, which may be
+// unlimitedAttempts, for String and PrintTo.
+func formatAttempts(attempts int) string {
+	if attempts == unlimitedAttempts {
+		return "unlimited"
+	}
+
+	return strconv.Itoa(attempts)
+}
+
+// fields returns all configuration options with their formatted values.
+func (rc *RetryConfig) fields() []configField {
+	return []configField{
+		{"attempts", formatAttempts(rc.attempts)},
+		{"baseDelay", rc.baseDelay.String()},
+		{"maxDelay", rc.maxDelay.String()},
+		{"minDelay", rc.minDelay.String()},
+		{"strategy", funcName(rc.delayType)},
+		{"logger", fmt.Sprintf("%T", rc.logger)},
+		{"onRetry", funcName(rc.onRetry)},
+		{"retryIf", funcName(rc.retryIf)},
+		{"logConfigOnStart", fmt.Sprint(rc.logConfigOnStart)},
+		{"maxAttemptDuration", rc.maxAttemptDuration.String()},
+		{"abortFunc", funcName(rc.abortFunc)},
+		{"attemptWindow", rc.attemptWindow.String()},
+		{"dynamicAttempts", funcName(rc.dynamicAttempts)},
+		{"dynamicDelay", funcName(rc.dynamicDelay)},
+		{"offHours", funcName(rc.allowFn)},
+		{"delayObserver", funcName(rc.delayObserver)},
+		{"slog", fmt.Sprint(rc.slog != nil)},
+		{"recoverPanics", fmt.Sprint(rc.recoverPanics)},
+		{"panicHandler", funcName(rc.panicHandler)},
+		{"contextTagger", funcName(rc.contextTagger)},
+		{"progress", fmt.Sprint(rc.progress != nil)},
+		{"costBudget", fmt.Sprintf("%g %s", rc.costBudget, funcName(rc.attemptCost))},
+		{"telemetry", fmt.Sprintf("%T", rc.telemetry)},
+		{"failFast", fmt.Sprint(rc.failFast)},
+		{"maxConcurrent", fmt.Sprint(rc.maxConcurrent)},
+		{"deadlineMargin", rc.deadlineMargin.String()},
+		{"sleepFunc", funcName(rc.sleepFn)},
+		{"requestID", rc.requestID},
+		{"delayMiddleware", fmt.Sprint(len(rc.delayMiddleware))},
+		{"attemptFilter", funcName(rc.attemptFilter)},
+		{"delayCap", funcName(rc.delayCap)},
+		{"mutex", fmt.Sprint(rc.mu != nil)},
+		{"circuitBreaker", fmt.Sprintf("%T", rc.breaker)},
+		{"contextValues", fmt.Sprint(len(rc.contextValues))},
+		{"attemptLogger", funcName(rc.attemptLogger)},
+		{"zeroValueErr", fmt.Sprint(rc.zeroValueErr)},
+		{"multiError", fmt.Sprint(rc.multiError)},
+		{"atomicStats", fmt.Sprint(rc.stats != nil)},
+		{"abortKeys", fmt.Sprint(len(rc.abortKeys))},
+		{"budgetKey", rc.budgetKey},
+		{"maxElapsedTime", rc.maxElapsed.String()},
+		{"clock", fmt.Sprintf("%T", rc.clock)},
+		{"errorWindow", fmt.Sprintf("%d %s", rc.errorWindowSize, funcName(rc.errorWindowFunc))},
+	}
+}
+
+// funcName returns a short, readable name for the given function value.
+// Package paths and compiler-generated closure suffixes are stripped, so
+// the closure returned by FixedDelay() is reported as "FixedDelay".
+// It returns "none" for nil functions.
+func funcName(fn any) string {
+	v := reflect.ValueOf(fn)
+	if fn == nil || v.Kind() != reflect.Func || v.IsNil() {
+		return "none"
+	}
+
+	f := runtime.FuncForPC(v.Pointer())
+	if f == nil {
+		return "unknown"
+	}
+
+	name := f.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, ".func"); i >= 0 {
+		name = name[:i]
+	}
+
+	return name
 }
 
 // NewRetry creates a new RetryConfig with sensible default values and applies
@@ -43,6 +261,7 @@ type RetryConfig struct {
 //   - 1s maximum delay
 //   - Fixed delay strategy
 //   - Silent logging (nopLogger)
+//   - No OnRetry hook
 //
 // Example:
 //
@@ -52,44 +271,89 @@ type RetryConfig struct {
 //	    retry.WithDelayType(retry.ExpBackoffWithJitter()),
 //	)
 func NewRetry(opts ...Option) *RetryConfig {
+	retry := applyOptions(opts)
+	retry.complete()
+
+	return retry
+}
+
+// applyOptions returns a RetryConfig with the defaults of NewRetry and the
+// given options applied, before complete normalizes it.
+func applyOptions(opts []Option) *RetryConfig {
 	retry := &RetryConfig{
 		attempts:  3,
 		baseDelay: 100 * time.Millisecond,
 		maxDelay:  1 * time.Second,
 		delayType: FixedDelay(),
 		logger:    nopLogger{},
-		onRetry:   func(attempt int, err error, delay time.Duration) {},
+		allowPoll: time.Minute,
+		clock:     realClock{},
 	}
 
 	for _, opt := range opts {
 		opt(retry)
 	}
 
+	return retry
+}
+
+// complete finishes a configuration created by applyOptions: it registers
+// the breaker transition callback, wraps the logger for the request ID and
+// raises maxDelay to baseDelay. RetryBuilder.Build calls it only after
+// validate, which would otherwise never see a negative maxDelay.
+func (rc *RetryConfig) complete() {
+	if rc.breaker != nil && rc.breakerTransition != nil {
+		rc.breaker.OnTransition(rc.breakerTransition, rc.breakerTransition.fn)
+	}
+
+	if rc.requestID != "" {
+		rc.logger = requestIDLogger{id: rc.requestID, logger: rc.logger}
+	}
+
 	// maxDelay validation in case a client forgot to set maxDelay
 	// with baseDelay or set it less than baseDelay
-	if retry.maxDelay < retry.baseDelay {
-		retry.maxDelay = retry.baseDelay
+	if rc.maxDelay < rc.baseDelay {
+		rc.maxDelay = rc.baseDelay
 	}
+}
 
-	return retry
+// validate checks the configuration for values that cannot work, such as a
+// non-positive number of attempts or negative durations. The returned error
+// wraps ErrInvalidConfig.
+func (rc *RetryConfig) validate() error {
+	switch {
+	case rc.attempts < 1 && rc.dynamicAttempts == nil:
+		return fmt.Errorf("%w: attempts must be positive, got %d", ErrInvalidConfig, rc.attempts)
+	case rc.baseDelay < 0:
+		return fmt.Errorf("%w: base delay must not be negative, got %v", ErrInvalidConfig, rc.baseDelay)
+	case rc.minDelay < 0:
+		return fmt.Errorf("%w: min delay must not be negative, got %v", ErrInvalidConfig, rc.minDelay)
+	case rc.maxDelay < 0:
+		return fmt.Errorf("%w: max delay must not be negative, got %v", ErrInvalidConfig, rc.maxDelay)
+	case rc.maxAttemptDuration < 0:
+		return fmt.Errorf("%w: max attempt duration must not be negative, got %v", ErrInvalidConfig, rc.maxAttemptDuration)
+	case rc.attemptWindow < 0:
+		return fmt.Errorf("%w: attempt window must not be negative, got %v", ErrInvalidConfig, rc.attemptWindow)
+	}
+
+	return nil
 }
 
 // RetryFunc defines the signature for operations that can be retried.
-// The function should return the resource and any error that occurred.
-//
+// The function returns a result of any type T and any error that occurred;
+// Do returns the result of the first successful attempt. Operations that
+// only return an error can use DoVoid.
 //
 // Example:
-
-// Be careful, the "string" is for reference here, you have to
-// replace with your specific type.
-//	retryFunc := func() (string, error) {
-//	    resp, err := http.Get("https://api.example.com/data")
+//
+//	retryFunc := func() (*User, error) {
+//	    resp, err := http.Get("https://api.example.com/user")
 //	    if err != nil {
-//	        return "", err
+//	        return nil, err
 //	    }
-//		defer resp.Body.Close()
-
-//	    return "success", nil
+//	    defer resp.Body.Close()
+//
+//	    return decodeUser(resp.Body)
 //	}
 type RetryFunc[T any] func() (T, error)
 
@@ -100,7 +364,13 @@ type RetryFunc[T any] func() (T, error)
 // The method handles:
 //   - Context cancellation (respects ctx.Done())
 //   - Non-retryable errors (marked with NonRetryable())
+//   - Ignored errors (marked with IgnoreError()), treated as success
+//   - Terminal errors (marked with SuccessOnError()), returning the partial result
 //   - Delay calculation and sleeping between attempts
+//   - Stopping early when a delay would outlast the context deadline
+//   - Delays requested by the operation (marked with RetryAfter())
+//   - Early exit based on the pattern of recent errors (WithErrorWindow())
+//   - Retries that do not consume the budget (marked with SoftRetry())
 //   - Comprehensive logging of retry events
 //
 // Returns the successful result or the last error encountered after all
@@ -114,50 +384,507 @@ type RetryFunc[T any] func() (T, error)
 //	    log.Fatal("All retry attempts failed:", err)
 //	}
 func Do[T any](ctx context.Context, rc *RetryConfig, fn RetryFunc[T]) (T, error) {
+	return do(ctx, rc, fn, loopState{})
+}
+
+// observer receives the events of a retry loop, e.g. to build a Trace.
+type observer interface {
+	record(kind TraceEventKind, attempt int, err error, delay time.Duration)
+}
+
+// loopState holds per-call settings of the retry loop that are not part of
+// the shared RetryConfig.
+type loopState struct {
+	first    int      // Attempt number to start with, 0 or 1 for a fresh loop
+	observer observer // Receives loop events, may be nil
+}
+
+// record forwards an event to the observer, if any.
+func (l loopState) record(kind TraceEventKind, attempt int, err error, delay time.Duration) {
+	if l.observer != nil {
+		l.observer.record(kind, attempt, err, delay)
+	}
+}
+
+// do implements Do and the variants built on top of it.
+func do[T any](ctx context.Context, rc *RetryConfig, fn RetryFunc[T], l loopState) (_ T, err error) {
 	var zero T
-	var lastErr error
+	var errs []error
+	loopStart := rc.clock.Now()
+
+	span := rc.startTelemetry(&ctx)
+	defer func() { span.finish(err) }()
+
+	rc.stats.recordCall()
+	defer func() { rc.stats.recordResult(err) }()
+
+	if rc.logConfigOnStart {
+		rc.logger.Printf("Starting retry with config: %s", rc)
+	}
+
+	if rc.progress != nil {
+		defer fmt.Fprintln(rc.progress)
+	}
+
+	var window []error
+	var spent float64
 
-	for attempt := 1; attempt <= rc.attempts; attempt++ {
+	// Attempts resumed by a session were made by earlier calls.
+	calls := max(l.first, 1) - 1
+
+	attempts := rc.maxAttempts()
+
+	for attempt := max(l.first, 1); attempt <= attempts; attempt++ {
 		if err := ctx.Err(); err != nil {
-			rc.logger.Printf("Context canceled before attempt %d: %v", attempt, err)
+			rc.loggerFor(attempt).Printf("Context canceled before attempt %d: %v", attempt, err)
 			return zero, fmt.Errorf("context canceled before attempt %d: %w", attempt, err)
 		}
 
-		data, err := fn()
+		if key, ok := rc.abortKey(ctx); ok && len(errs) > 0 {
+			lastErr := errs[len(errs)-1]
+			rc.loggerFor(attempt).Printf("Retry aborted by context value %v before attempt %d: %v", key, attempt, lastErr)
+			return zero, fmt.Errorf("retry aborted by context value %v before attempt %d: %w", key, attempt, lastErr)
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && rc.deadlineMargin > 0 && time.Until(deadline) <= rc.deadlineMargin {
+			rc.loggerFor(attempt).Printf("Context deadline within %v before attempt %d", rc.deadlineMargin, attempt)
+			return zero, fmt.Errorf("context deadline within margin before attempt %d: %w", attempt, context.DeadlineExceeded)
+		}
+
+		if rc.costBudget > 0 && rc.attemptCost != nil {
+			spent += rc.attemptCost(attempt)
+			if spent > rc.costBudget {
+				rc.loggerFor(attempt).Printf("Cost budget of %g exceeded before attempt %d", rc.costBudget, attempt)
+				if len(errs) == 0 {
+					return zero, fmt.Errorf("%w before attempt %d", ErrCostBudgetExceeded, attempt)
+				}
+				return zero, fmt.Errorf("%w before attempt %d: %w", ErrCostBudgetExceeded, attempt, errs[len(errs)-1])
+			}
+		}
+
+		if len(errs) > 0 && !rc.acquireBudget() {
+			rc.loggerFor(attempt).Printf("Retry budget %q exhausted before attempt %d", rc.budgetKey, attempt)
+			return zero, fmt.Errorf("%w: %q before attempt %d: %w", ErrRetryBudgetExhausted, rc.budgetKey, attempt, errs[len(errs)-1])
+		}
+
+		if rc.breaker != nil {
+			if err := rc.breaker.Allow(); err != nil {
+				rc.loggerFor(attempt).Printf("Circuit breaker rejected attempt %d: %v", attempt, err)
+				return zero, fmt.Errorf("attempt %d rejected: %w", attempt, err)
+			}
+		}
+
+		rc.writeProgress(attempt, attempts)
+
+		start := rc.clock.Now()
+		l.record(EventAttemptStart, attempt, nil, 0)
+		rc.stats.recordAttempt()
+		calls++
+		data, err := call(rc, attempt, fn)
+		span.attemptDone(attempt, err)
+		if rc.breaker != nil {
+			if err == nil || isSuccess(err) || isIgnored(err) {
+				rc.breaker.RecordSuccess()
+			} else {
+				rc.breaker.RecordFailure()
+			}
+		}
+		l.record(EventAttemptResult, attempt, err, 0)
 		if err == nil {
 			return data, nil
 		}
 
-		lastErr = err
+		if isSuccess(err) {
+			rc.loggerFor(attempt).Printf("Terminal success condition on attempt %d: %v", attempt, err)
+			return data, nil
+		}
+
+		if isIgnored(err) {
+			rc.loggerFor(attempt).Printf("Ignored error on attempt %d: %v", attempt, err)
+			return zero, nil
+		}
+
+		errs = rc.recordError(errs, err, attempts)
 
-		if !isRetryable(err) {
-			rc.logger.Printf("Non-retryable error on attempt %d: %v", attempt, err)
+		if !rc.shouldRetry(err) {
+			rc.loggerFor(attempt).Printf("Non-retryable error on attempt %d: %v", attempt, err)
+			rc.logAttrs(ctx, slog.LevelError, "non-retryable error", attempt, attempts, 0, err)
 			return zero, fmt.Errorf("non-retryable error: %w", err)
 		}
 
-		if attempt == rc.attempts {
+		if rc.failFast && attempt == 1 {
+			if elapsed := rc.clock.Now().Sub(start); elapsed > rc.baseDelay {
+				rc.loggerFor(attempt).Printf("First attempt failed after %v, failing fast: %v", elapsed, err)
+				return zero, fmt.Errorf("fail fast after slow first attempt (%v): %w", elapsed, err)
+			}
+		}
+
+		soft := isSoftRetry(err)
+
+		if attempt == attempts && !soft {
 			break
 		}
 
-		delay := rc.baseDelay
-		if rc.delayType != nil {
-			delay = rc.delayType(attempt, rc.baseDelay, rc.maxDelay)
+		skipped := 0
+		if rc.attemptFilter != nil && !soft {
+			for attempt+skipped < attempts && skipped < maxSkippedAttempts && !rc.attemptFilter(attempt+skipped+1, err) {
+				skipped++
+			}
+			if attempt+skipped == attempts {
+				rc.loggerFor(attempt).Printf("Remaining attempts skipped by filter after attempt %d: %v", attempt, err)
+				break
+			}
+			if skipped == maxSkippedAttempts {
+				rc.loggerFor(attempt).Printf("%d consecutive attempts skipped by filter after attempt %d: %v", skipped, attempt, err)
+				break
+			}
+		}
+
+		if rc.errorWindowFunc != nil && !soft {
+			window = append(window, err)
+			if len(window) > rc.errorWindowSize {
+				window = window[1:]
+			}
+
+			if len(window) == rc.errorWindowSize && !rc.errorWindowFunc(slices.Clone(window)) {
+				rc.loggerFor(attempt).Printf("Retry aborted by error window on attempt %d: %v", attempt, err)
+				return zero, fmt.Errorf("retry aborted by error window on attempt %d: %w", attempt, err)
+			}
+		}
+
+		if err := rc.waitUntilAllowed(ctx); err != nil {
+			rc.loggerFor(attempt).Printf("Retry canceled by context outside allowed hours on attempt %d: %v", attempt, err)
+			return zero, fmt.Errorf("retry canceled by context on attempt %d: %w", attempt, err)
+		}
+
+		delay := rc.delayAfter(attempt, rc.clock.Now().Sub(start))
+		if d, ok := retryAfterDelay(err); ok {
+			delay = d
+		}
+
+		if elapsed := rc.clock.Now().Sub(loopStart); rc.maxElapsed > 0 && elapsed+delay > rc.maxElapsed {
+			rc.loggerFor(attempt).Printf("Max elapsed time of %v would be exceeded after attempt %d: %v", rc.maxElapsed, attempt, err)
+			return zero, fmt.Errorf("%w after %v on attempt %d: %w", ErrMaxElapsedTimeExceeded, elapsed.Round(time.Millisecond), attempt, err)
+		}
+
+		// Sleeping past the deadline, or into the margin before it, only
+		// delays the inevitable context error.
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline)-delay <= rc.deadlineMargin {
+			rc.loggerFor(attempt).Printf("Delay of %v would exceed the context deadline after attempt %d: %v", delay, attempt, err)
+			return zero, fmt.Errorf("delay of %v would exceed the context deadline after attempt %d: %w: %w", delay, attempt, context.DeadlineExceeded, err)
+		}
+
+		if rc.onRetry != nil {
+			rc.lock()
+			rc.onRetry(attempt, err, delay)
+			rc.unlock()
 		}
 
-		rc.onRetry(attempt, err, delay)
+		rc.loggerFor(attempt).Printf("Attempt %d failed: %v. Retrying in %v...\n", attempt, err, delay)
+		rc.logAttrs(ctx, slog.LevelWarn, "attempt failed, retrying", attempt, attempts, delay, err)
 
-		rc.logger.Printf("Attempt %d failed: %v. Retrying in %v...\n", attempt, err, delay)
+		span.delay(delay)
+		rc.stats.recordDelay(delay)
+		l.record(EventDelayStart, attempt, nil, delay)
+		sleepStart := rc.clock.Now()
+		sleepErr := rc.sleep(ctx, delay)
+		l.record(EventDelayEnd, attempt, sleepErr, delay)
+		if sleepErr != nil {
+			rc.loggerFor(attempt).Printf("Retry canceled by context on attempt %d: %v", attempt, sleepErr)
+			rc.logAttrs(ctx, slog.LevelError, "retry canceled by context", attempt, attempts, delay, sleepErr)
+			return zero, fmt.Errorf("retry canceled by context on attempt %d: %w", attempt, sleepErr)
+		}
 
-		timer := time.NewTimer(delay)
-		select {
-		case <-ctx.Done():
-			timer.Stop()
-			rc.logger.Printf("Retry canceled by context on attempt %d: %v", attempt, ctx.Err())
-			return zero, fmt.Errorf("retry canceled by context on attempt %d: %w", attempt, ctx.Err())
-		case <-timer.C:
+		if rc.delayObserver != nil {
+			rc.lock()
+			rc.delayObserver(delay, rc.clock.Now().Sub(sleepStart))
+			rc.unlock()
 		}
+
+		if soft {
+			// Soft retries do not consume the budget, so the same
+			// attempt number is used again.
+			attempt--
+		}
+
+		// Attempts rejected by the filter consume the budget without
+		// being made.
+		attempt += skipped
 	}
 
-	rc.logger.Printf("All %d attempts failed. Last error: %v", rc.attempts, lastErr)
-	return zero, fmt.Errorf("all attempts failed, the last error: %w", lastErr)
+	exhausted := rc.exhaustedError(calls, errs, rc.clock.Now().Sub(loopStart))
+	rc.loggerFor(attempts).Printf("All %d attempts failed. Last error: %v", calls, exhausted.last())
+	rc.logAttrs(ctx, slog.LevelError, "all attempts failed", attempts, attempts, 0, exhausted.last())
+	return zero, exhausted
+}
+
+// recordError appends the error of a failed attempt to errs for a retry
+// loop of the given number of attempts. Unless WithMultiError is set, a loop
+// with unlimited attempts keeps only the last error, as no other is used, so
+// memory does not grow with the number of attempts.
+func (rc *RetryConfig) recordError(errs []error, err error, attempts int) []error {
+	if attempts == unlimitedAttempts && !rc.multiError {
+		return append(errs[:0], err)
+	}
+	return append(errs, err)
+}
+
+// exhaustedError returns the error of a retry loop that gave up after
+// calls calls of the operation failed with errs and took elapsed. Without
+// errs, the last error is ErrNoAttempts.
+func (rc *RetryConfig) exhaustedError(calls int, errs []error, elapsed time.Duration) *ExhaustedError {
+	if len(errs) == 0 {
+		errs = []error{ErrNoAttempts}
+	}
+	return &ExhaustedError{attempts: calls, errs: errs, multi: rc.multiError, elapsed: elapsed}
+}
+
+// call runs a single attempt of fn. Without a maximum attempt duration fn is
+// called directly. Otherwise fn runs in its own goroutine and the attempt is
+// abandoned with ErrAttemptTimedOut once maxAttemptDuration elapses; the
+// abort function, if any, is then called from a dedicated goroutine.
+func call[T any](rc *RetryConfig, attempt int, fn RetryFunc[T]) (T, error) {
+	if rc.zeroValueErr != nil {
+		fn = rejectingZero(rc.zeroValueErr, fn)
+	}
+
+	if rc.recoverPanics {
+		fn = recovering(rc, attempt, fn)
+	}
+
+	if rc.maxAttemptDuration <= 0 {
+		return fn()
+	}
+
+	type result struct {
+		data T
+		err  error
+	}
+
+	// Buffered so an abandoned attempt can still deliver its result
+	// and exit without blocking forever.
+	done := make(chan result, 1)
+	go func() {
+		data, err := fn()
+		done <- result{data: data, err: err}
+	}()
+
+	timer := rc.clock.NewTimer(rc.maxAttemptDuration)
+	defer timer.Stop()
+
+	select {
+	case res := <-done:
+		return res.data, res.err
+	case <-timer.C():
+		if rc.abortFunc != nil {
+			go rc.abortFunc(attempt)
+		}
+
+		var zero T
+		return zero, fmt.Errorf("attempt %d exceeded %v: %w", attempt, rc.maxAttemptDuration, ErrAttemptTimedOut)
+	}
+}
+
+// recovering wraps fn so that a panic is reported to the panic handler, if
+// any, and returned as an error wrapping ErrPanicked.
+func recovering[T any](rc *RetryConfig, attempt int, fn RetryFunc[T]) RetryFunc[T] {
+	return func() (data T, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				if rc.panicHandler != nil {
+					rc.lock()
+					rc.panicHandler(attempt, r)
+					rc.unlock()
+				}
+
+				var zero T
+				data, err = zero, fmt.Errorf("%w on attempt %d: %v", ErrPanicked, attempt, r)
+			}
+		}()
+
+		return fn()
+	}
+}
+
+// abortKey returns the first key set with WithAbortOnContextValues whose
+// value in ctx is truthy, i.e. neither nil nor the zero value of its type.
+func (rc *RetryConfig) abortKey(ctx context.Context) (any, bool) {
+	for _, key := range rc.abortKeys {
+		if !isZero(ctx.Value(key)) {
+			return key, true
+		}
+	}
+
+	return nil, false
+}
+
+// rejectingZero wraps fn so that a zero result with a nil error is
+// reported as zeroErr. Results of type struct{}, as used by DoVoid and the
+// other helpers without a result, are never zero-checked.
+func rejectingZero[T any](zeroErr error, fn RetryFunc[T]) RetryFunc[T] {
+	if _, void := any(*new(T)).(struct{}); void {
+		return fn
+	}
+
+	return func() (T, error) {
+		data, err := fn()
+		if err == nil && isZero(data) {
+			return data, zeroErr
+		}
+		return data, err
+	}
+}
+
+// isZero reports whether v is the zero value of its type. A nil interface
+// value counts as zero.
+func isZero(v any) bool {
+	rv := reflect.ValueOf(v)
+	return !rv.IsValid() || rv.IsZero()
+}
+
+// shouldRetry reports whether the given error may be retried under this
+// configuration. Errors marked with NonRetryable() are never retried; all
+// other errors are additionally filtered by the WithRetryIf predicate.
+func (rc *RetryConfig) shouldRetry(err error) bool {
+	if !isRetryable(err) {
+		return false
+	}
+
+	return rc.matches(err)
+}
+
+// matches reports whether the WithRetryIf predicate accepts the error.
+// Without a predicate every error matches.
+func (rc *RetryConfig) matches(err error) bool {
+	return rc.retryIf == nil || rc.retryIf(err)
+}
+
+// maxAttempts returns the number of attempts of a retry loop, resolving the
+// WithDynamicAttempts function if there is one.
+func (rc *RetryConfig) maxAttempts() int {
+	if rc.dynamicAttempts != nil {
+		return rc.dynamicAttempts()
+	}
+	return rc.attempts
+}
+
+// delayAfter returns the delay to wait after the given failed attempt that
+// took the given time. With an attempt window the delay fills the rest of
+// the window, otherwise it is calculated by nextDelay.
+func (rc *RetryConfig) delayAfter(attempt int, took time.Duration) time.Duration {
+	if rc.attemptWindow > 0 {
+		return max(rc.attemptWindow-took, 0)
+	}
+	return rc.nextDelay(attempt)
+}
+
+// nextDelay calculates the delay to wait after the given failed attempt
+// using the dynamic delay function or the configured delay strategy, falling
+// back to the base delay. The strategy is wrapped with the configured delay
+// middlewares and capped by the WithDelayCap function, if any. The result is
+// never shorter than minDelay.
+func (rc *RetryConfig) nextDelay(attempt int) time.Duration {
+	rc.lock()
+	defer rc.unlock()
+
+	maxDelay := rc.maxDelay
+	if rc.delayCap != nil {
+		maxDelay = rc.delayCap(attempt)
+	}
+
+	var delay time.Duration
+	switch {
+	case rc.dynamicDelay != nil:
+		delay = rc.dynamicDelay(attempt)
+	case rc.delayType != nil:
+		delay = rc.wrapDelay(rc.delayType)(attempt, rc.baseDelay, maxDelay)
+	default:
+		delay = rc.wrapDelay(FixedDelay())(attempt, rc.baseDelay, maxDelay)
+	}
+
+	if rc.delayCap != nil && rc.dynamicDelay == nil {
+		delay = min(delay, maxDelay)
+	}
+
+	return max(delay, rc.minDelay)
+}
+
+// loggerFor returns the logger for the given attempt, which is the one
+// returned by the WithPerAttemptLogger function if it returns non-nil.
+func (rc *RetryConfig) loggerFor(attempt int) Logger {
+	if rc.attemptLogger == nil {
+		return rc.logger
+	}
+
+	logger := rc.attemptLogger(attempt)
+	if logger == nil {
+		return rc.logger
+	}
+	if rc.requestID != "" {
+		return requestIDLogger{id: rc.requestID, logger: logger}
+	}
+
+	return logger
+}
+
+// lock acquires the mutex set with WithMutex, if any.
+func (rc *RetryConfig) lock() {
+	if rc.mu != nil {
+		rc.mu.Lock()
+	}
+}
+
+// unlock releases the mutex set with WithMutex, if any.
+func (rc *RetryConfig) unlock() {
+	if rc.mu != nil {
+		rc.mu.Unlock()
+	}
+}
+
+// waitUntilAllowed blocks until the WithOffHours function allows retries,
+// polling it every allowPoll, or until the context is done. It returns the
+// context error if the wait was interrupted.
+func (rc *RetryConfig) waitUntilAllowed(ctx context.Context) error {
+	if rc.allowFn == nil {
+		return nil
+	}
+
+	for !rc.allowFn(rc.clock.Now()) {
+		if err := rc.sleep(ctx, rc.allowPoll); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sleep waits for the given delay using the function set with WithSleepFunc
+// or, by default, a timer of the clock that is interrupted when the context
+// is done. A custom sleep function cannot be interrupted, so the context is
+// only checked before and after it.
+func (rc *RetryConfig) sleep(ctx context.Context, delay time.Duration) error {
+	if rc.sleepFn == nil {
+		return sleep(ctx, rc.clock, delay)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	rc.sleepFn(delay)
+	return ctx.Err()
+}
+
+// sleep blocks for the given delay on clock or until the context is done,
+// whichever happens first. It returns the context error if the wait was
+// interrupted.
+func sleep(ctx context.Context, clock Clock, delay time.Duration) error {
+	timer := clock.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C():
+		return nil
+	}
 }
@@ -4,6 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -79,6 +84,59 @@ func TestDoSuccessCases(t *testing.T) {
 	}
 }
 
+// TestDoResultTypes verifies that Do returns results of arbitrary types
+// without conversion.
+func TestDoResultTypes(t *testing.T) {
+	t.Parallel()
+	type user struct {
+		id   int
+		name string
+	}
+	rc := NewRetry(WithDelay(time.Millisecond))
+
+	failOnce := func() func() error {
+		calls := 0
+		return func() error {
+			calls++
+			if calls == 1 {
+				return errors.New("first attempt error")
+			}
+			return nil
+		}
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		t.Parallel()
+		fail := failOnce()
+		got, err := Do(context.Background(), rc, func() (user, error) {
+			return user{id: 1, name: "gopher"}, fail()
+		})
+		if err != nil || got != (user{id: 1, name: "gopher"}) {
+			t.Errorf("expected the user, got %+v, %v", got, err)
+		}
+	})
+	t.Run("int", func(t *testing.T) {
+		t.Parallel()
+		fail := failOnce()
+		got, err := Do(context.Background(), rc, func() (int, error) {
+			return 42, fail()
+		})
+		if err != nil || got != 42 {
+			t.Errorf("expected 42, got %d, %v", got, err)
+		}
+	})
+	t.Run("slice", func(t *testing.T) {
+		t.Parallel()
+		fail := failOnce()
+		got, err := Do(context.Background(), rc, func() ([]string, error) {
+			return []string{"a", "b"}, fail()
+		})
+		if err != nil || !slices.Equal(got, []string{"a", "b"}) {
+			t.Errorf("expected [a b], got %v, %v", got, err)
+		}
+	})
+}
+
 // TestDoAllAttemptsFailed tests the Do method for scenarios where all retry
 // attempts fail. It verifies that an error is returned and the result is empty
 // when all attempts are unsuccessful.
@@ -209,3 +267,1249 @@ func TestDoContextCancelation(t *testing.T) {
 		})
 	}
 }
+
+// recordingLogger is a Logger implementation that stores every formatted
+// log line so tests can assert on the produced output.
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+// TestDoCancelInterruptsDelay verifies that canceling the context ends a
+// long delay immediately instead of after the delay has elapsed.
+func TestDoCancelInterruptsDelay(t *testing.T) {
+	t.Parallel()
+	rc := NewRetry(WithDelay(30*time.Second), WithMaxDelay(30*time.Second))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := Do(ctx, rc, func() (string, error) {
+		return "", errors.New("attempt error")
+	})
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled error, got %v", err)
+	}
+	if elapsed > 5*time.Second {
+		t.Errorf("expected the delay to be interrupted, Do took %v", elapsed)
+	}
+}
+
+// TestDoLogConfigOnStart verifies that WithLogConfigOnStart makes Do log the
+// effective configuration, including the delay strategy name, before the
+// first attempt.
+func TestDoLogConfigOnStart(t *testing.T) {
+	t.Parallel()
+	logger := &recordingLogger{}
+	rc := NewRetry(
+		WithLogger(logger),
+		WithLogConfigOnStart(true),
+		WithDelayType(ExpBackoffWithJitter()),
+	)
+
+	_, err := Do(context.Background(), rc, func() (string, error) {
+		return "success", nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(logger.lines) == 0 {
+		t.Fatal("expected configuration to be logged, got no output")
+	}
+
+	if !strings.Contains(logger.lines[0], "strategy=ExpBackoffWithJitter") {
+		t.Errorf("expected log line to contain strategy name, got %q", logger.lines[0])
+	}
+}
+
+// TestRetryConfigString verifies that String reports all configured values.
+func TestRetryConfigString(t *testing.T) {
+	rc := NewRetry(WithAttempts(5), WithDelay(200*time.Millisecond), WithMaxDelay(2*time.Second))
+
+	want := "attempts=5 baseDelay=200ms maxDelay=2s strategy=FixedDelay onRetry=false"
+	if got := rc.String(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// TestDoIgnoredError verifies that an error marked with IgnoreError stops
+// the retry loop and is reported as success with the zero value.
+func TestDoIgnoredError(t *testing.T) {
+	t.Parallel()
+	rc := NewRetry()
+	calls := 0
+
+	result, err := Do(context.Background(), rc, func() (string, error) {
+		calls++
+		return "partial", IgnoreError(errors.New("key already exists"))
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+
+	if result != "" {
+		t.Errorf("expected zero value, got '%s'", result)
+	}
+}
+
+// TestDoSuccessOnError verifies that an error marked with SuccessOnError
+// stops the retry loop and returns the partial result with a nil error.
+func TestDoSuccessOnError(t *testing.T) {
+	t.Parallel()
+	rc := NewRetry()
+	calls := 0
+
+	result, err := Do(context.Background(), rc, func() (int, error) {
+		calls++
+		if calls == 1 {
+			return 0, errors.New("transient error")
+		}
+		return 42, SuccessOnError(io.EOF)
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+
+	if result != 42 {
+		t.Errorf("expected partial result 42, got %d", result)
+	}
+}
+
+// TestDoMaxAttemptDuration verifies that an attempt ignoring its deadline is
+// abandoned after the configured duration, that the abort function is
+// called, and that the next attempt is made.
+func TestDoMaxAttemptDuration(t *testing.T) {
+	t.Parallel()
+	aborted := make(chan int, 1)
+	release := make(chan struct{})
+	defer close(release)
+
+	rc := NewRetry(
+		WithDelay(time.Millisecond),
+		WithMaxAttemptDuration(50*time.Millisecond),
+		WithAbortFunc(func(attempt int) { aborted <- attempt }),
+	)
+
+	var calls atomic.Int32
+	result, err := Do(context.Background(), rc, func() (string, error) {
+		if calls.Add(1) == 1 {
+			<-release
+			return "too late", nil
+		}
+		return "success", nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if result != "success" {
+		t.Errorf("expected 'success', got '%s'", result)
+	}
+
+	select {
+	case attempt := <-aborted:
+		if attempt != 1 {
+			t.Errorf("expected abort for attempt 1, got %d", attempt)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected abort function to be called")
+	}
+}
+
+// TestDoMaxAttemptDurationExhausted verifies that timed out attempts are
+// reported with ErrAttemptTimedOut once all attempts are used up.
+func TestDoMaxAttemptDurationExhausted(t *testing.T) {
+	t.Parallel()
+	release := make(chan struct{})
+	defer close(release)
+
+	rc := NewRetry(
+		WithAttempts(2),
+		WithDelay(time.Millisecond),
+		WithMaxAttemptDuration(10*time.Millisecond),
+	)
+
+	_, err := Do(context.Background(), rc, func() (string, error) {
+		<-release
+		return "", nil
+	})
+	if !errors.Is(err, ErrAttemptTimedOut) {
+		t.Fatalf("expected ErrAttemptTimedOut, got %v", err)
+	}
+}
+
+// TestRetryConfigPrintTo verifies that PrintTo writes every option on its
+// own line and reports the number of bytes written.
+func TestRetryConfigPrintTo(t *testing.T) {
+	rc := NewRetry(
+		WithAttempts(7),
+		WithDelay(250*time.Millisecond),
+		WithMaxDelay(3*time.Second),
+		WithDelayType(ExpBackoffWithJitter()),
+		WithLogConfigOnStart(true),
+		WithMaxAttemptDuration(2*time.Second),
+	)
+
+	var buf strings.Builder
+	n, err := rc.PrintTo(&buf)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	out := buf.String()
+	if n != len(out) {
+		t.Errorf("expected %d bytes written, got %d", len(out), n)
+	}
+
+	for _, want := range []string{
+		"attempts:", "7",
+		"baseDelay:", "250ms",
+		"maxDelay:", "3s",
+		"strategy:", "ExpBackoffWithJitter",
+		"logger:", "retry.nopLogger",
+		"onRetry:", "retryIf:", "none",
+		"logConfigOnStart:", "true",
+		"maxAttemptDuration:", "2s",
+		"abortFunc:",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	if lines := strings.Count(out, "\n"); lines != len(rc.fields()) {
+		t.Errorf("expected %d lines, got %d", len(rc.fields()), lines)
+	}
+}
+
+// TestDoTimeBasedAttempts verifies that attempts start roughly every window,
+// regardless of their execution time, and that the loop ends near budget.
+func TestDoTimeBasedAttempts(t *testing.T) {
+	t.Parallel()
+	window := 50 * time.Millisecond
+	budget := 200 * time.Millisecond
+	rc := NewRetry(WithTimeBasedAttempts(window, budget))
+
+	var starts []time.Time
+	begin := time.Now()
+	_, err := Do(context.Background(), rc, func() (string, error) {
+		starts = append(starts, time.Now())
+		time.Sleep(20 * time.Millisecond)
+		return "", errors.New("attempt error")
+	})
+	elapsed := time.Since(begin)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if len(starts) != 4 {
+		t.Fatalf("expected 4 attempts, got %d", len(starts))
+	}
+
+	for i := 1; i < len(starts); i++ {
+		gap := starts[i].Sub(starts[i-1])
+		if gap < window || gap > window+40*time.Millisecond {
+			t.Errorf("attempt %d started %v after the previous one, want ~%v", i+1, gap, window)
+		}
+	}
+
+	if elapsed < budget-window || elapsed > budget+100*time.Millisecond {
+		t.Errorf("expected loop to end near %v, took %v", budget, elapsed)
+	}
+}
+
+// TestDoDynamicAttempts verifies that the attempt count is resolved once per
+// Do call and follows runtime changes between calls.
+func TestDoDynamicAttempts(t *testing.T) {
+	t.Parallel()
+	var maxAttempts atomic.Int64
+	maxAttempts.Store(4)
+	resolved := 0
+
+	rc := NewRetry(
+		WithDelay(time.Millisecond),
+		WithDynamicAttempts(func() int {
+			resolved++
+			return int(maxAttempts.Add(-1) + 1)
+		}),
+	)
+
+	for _, expected := range []int{4, 3, 2} {
+		calls := 0
+		_, err := Do(context.Background(), rc, func() (string, error) {
+			calls++
+			return "", errors.New("attempt error")
+		})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if calls != expected {
+			t.Errorf("expected %d calls, got %d", expected, calls)
+		}
+	}
+
+	if resolved != 3 {
+		t.Errorf("expected attempt count to be resolved once per Do call, got %d", resolved)
+	}
+}
+
+// TestDoDynamicDelay verifies that the delay is resolved at sleep time, so a
+// change made by another goroutine during the session is picked up.
+func TestDoDynamicDelay(t *testing.T) {
+	t.Parallel()
+	var current atomic.Int64
+	current.Store(int64(time.Millisecond))
+	changed := make(chan struct{})
+
+	var delays []time.Duration
+	rc := NewRetry(
+		WithAttempts(4),
+		WithDynamicDelay(func(int) time.Duration { return time.Duration(current.Load()) }),
+		WithOnRetry(func(_ int, _ error, delay time.Duration) { delays = append(delays, delay) }),
+	)
+
+	calls := 0
+	_, err := Do(context.Background(), rc, func() (string, error) {
+		calls++
+		if calls == 2 {
+			go func() {
+				current.Store(int64(2 * time.Millisecond))
+				close(changed)
+			}()
+			<-changed
+		}
+		return "", errors.New("attempt error")
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	want := []time.Duration{time.Millisecond, 2 * time.Millisecond, 2 * time.Millisecond}
+	if len(delays) != len(want) {
+		t.Fatalf("expected %d delays, got %v", len(want), delays)
+	}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Errorf("delay %d: expected %v, got %v", i+1, want[i], delays[i])
+		}
+	}
+}
+
+// TestDoErrorWindow verifies that the window function receives the last n
+// errors once the window is full and that returning false aborts retrying.
+func TestDoErrorWindow(t *testing.T) {
+	t.Parallel()
+	errC := errors.New("c")
+	errs := []error{errors.New("a"), errors.New("b"), errC, errC, errC}
+
+	var windows [][]error
+	rc := NewRetry(
+		WithAttempts(10),
+		WithDelay(time.Millisecond),
+		WithErrorWindow(2, func(window []error) bool {
+			windows = append(windows, window)
+			return window[0] != window[1]
+		}),
+	)
+
+	calls := 0
+	_, err := Do(context.Background(), rc, func() (string, error) {
+		err := errs[calls]
+		calls++
+		return "", err
+	})
+	if !errors.Is(err, errs[3]) {
+		t.Fatalf("expected error from attempt 4, got %v", err)
+	}
+
+	if calls != 4 {
+		t.Errorf("expected 4 calls, got %d", calls)
+	}
+
+	if len(windows) != 3 {
+		t.Fatalf("expected window function to be called 3 times, got %d", len(windows))
+	}
+
+	if windows[0][0] != errs[0] || windows[0][1] != errs[1] {
+		t.Errorf("expected first window to hold the first two errors, got %v", windows[0])
+	}
+}
+
+// fakeClock is a manually advanced time source for tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// NewTimer advances the clock by d and returns a timer that has already
+// fired, so delays take no real time.
+func (c *fakeClock) NewTimer(d time.Duration) Timer {
+	c.Advance(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.Now()
+	return firedTimer(ch)
+}
+
+// firedTimer is a Timer whose time has already been delivered.
+type firedTimer chan time.Time
+
+func (t firedTimer) C() <-chan time.Time { return t }
+
+func (t firedTimer) Stop() bool { return false }
+
+// TestDoOffHours verifies that retries wait while the allow function
+// rejects the current time and resume once it accepts it.
+func TestDoOffHours(t *testing.T) {
+	t.Parallel()
+	clock := &fakeClock{now: time.Date(2025, 1, 6, 16, 0, 0, 0, time.UTC)}
+	closing := time.Date(2025, 1, 6, 17, 0, 0, 0, time.UTC)
+	opening := time.Date(2025, 1, 7, 9, 0, 0, 0, time.UTC)
+
+	checks := 0
+	rc := NewRetry(
+		WithDelay(time.Millisecond),
+		WithOffHours(func(now time.Time) bool {
+			checks++
+			// Every check while closed moves the clock forward by an hour.
+			if now.Before(closing) || !now.Before(opening) {
+				return true
+			}
+			clock.Advance(time.Hour)
+			return false
+		}),
+	)
+	rc.clock = clock
+	rc.allowPoll = time.Millisecond
+
+	calls := 0
+	result, err := Do(context.Background(), rc, func() (string, error) {
+		calls++
+		if calls == 1 {
+			clock.Advance(2 * time.Hour) // The first attempt ends after closing time.
+			return "", errors.New("attempt error")
+		}
+		return "success", nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if result != "success" {
+		t.Errorf("expected 'success', got '%s'", result)
+	}
+
+	if now := clock.Now(); now.Before(opening) {
+		t.Errorf("expected retry to wait until %v, resumed at %v", opening, now)
+	}
+
+	if checks < 2 {
+		t.Errorf("expected allow function to be polled, got %d check(s)", checks)
+	}
+}
+
+// TestDoOffHoursCanceled verifies that waiting outside allowed hours is
+// interrupted by context cancellation.
+func TestDoOffHoursCanceled(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	rc := NewRetry(WithOffHours(func(time.Time) bool { return false }))
+
+	_, err := Do(ctx, rc, func() (string, error) {
+		return "", errors.New("attempt error")
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+// TestDoSoftRetry verifies that soft retries do not consume the budget and
+// do not advance the attempt counter.
+func TestDoSoftRetry(t *testing.T) {
+	t.Parallel()
+	var attempts []int
+	rc := NewRetry(
+		WithAttempts(2),
+		WithDelay(time.Millisecond),
+		WithOnRetry(func(attempt int, _ error, _ time.Duration) { attempts = append(attempts, attempt) }),
+	)
+
+	calls := 0
+	_, err := Do(context.Background(), rc, func() (string, error) {
+		calls++
+		if calls <= 3 {
+			return "", SoftRetry(errors.New("not ready"))
+		}
+		return "", errors.New("attempt error")
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if calls != 5 {
+		t.Errorf("expected 3 soft and 2 regular calls, got %d", calls)
+	}
+
+	want := []int{1, 1, 1, 1}
+	if !slices.Equal(attempts, want) {
+		t.Errorf("expected attempt numbers %v, got %v", want, attempts)
+	}
+}
+
+// TestDoDelayObserver verifies that the observer receives the planned delay
+// and a measured duration of at least that delay.
+func TestDoDelayObserver(t *testing.T) {
+	t.Parallel()
+	var planned, actual []time.Duration
+	rc := NewRetry(
+		WithAttempts(3),
+		WithDelay(10*time.Millisecond),
+		WithDelayObserver(func(p, a time.Duration) {
+			planned = append(planned, p)
+			actual = append(actual, a)
+		}),
+	)
+
+	_, _ = Do(context.Background(), rc, func() (string, error) {
+		return "", errors.New("attempt error")
+	})
+
+	if len(planned) != 2 {
+		t.Fatalf("expected 2 observed delays, got %d", len(planned))
+	}
+
+	for i := range planned {
+		if planned[i] != 10*time.Millisecond {
+			t.Errorf("delay %d: expected planned 10ms, got %v", i+1, planned[i])
+		}
+		if actual[i] < planned[i] {
+			t.Errorf("delay %d: expected actual >= planned, got %v < %v", i+1, actual[i], planned[i])
+		}
+	}
+}
+
+// TestDoExhaustedError verifies that exhaustion is reported with an
+// ExhaustedError holding the errors of all attempts.
+func TestDoExhaustedError(t *testing.T) {
+	t.Parallel()
+	rc := NewRetry(WithAttempts(3), WithDelay(time.Millisecond))
+	attemptErrs := []error{errors.New("first"), errors.New("second"), errors.New("third")}
+
+	calls := 0
+	_, err := Do(context.Background(), rc, func() (string, error) {
+		err := attemptErrs[calls]
+		calls++
+		return "", err
+	})
+
+	if !IsExhausted(err) || !errors.Is(err, ErrExhausted) {
+		t.Fatalf("expected exhausted error, got %v", err)
+	}
+
+	if !errors.Is(err, attemptErrs[2]) {
+		t.Error("expected last error to be reachable with errors.Is")
+	}
+
+	// Without WithMultiError earlier errors are kept but not unwrapped.
+	if errors.Is(err, attemptErrs[0]) || errors.Is(err, attemptErrs[1]) {
+		t.Error("expected earlier errors not to be reachable with errors.Is")
+	}
+
+	var exhausted *ExhaustedError
+	if !errors.As(err, &exhausted) {
+		t.Fatalf("expected *ExhaustedError, got %T", err)
+	}
+
+	if !exhausted.IsExhausted() || exhausted.TotalAttempts() != 3 {
+		t.Errorf("expected 3 total attempts, got %d", exhausted.TotalAttempts())
+	}
+
+	if !slices.Equal(exhausted.AllErrors(), attemptErrs) {
+		t.Errorf("expected all attempt errors %v, got %v", attemptErrs, exhausted.AllErrors())
+	}
+
+	if exhausted.Elapsed() < 2*time.Millisecond {
+		t.Errorf("expected the elapsed time to include both delays, got %v", exhausted.Elapsed())
+	}
+
+	if err.Error() != "all attempts failed, the last error: third" {
+		t.Errorf("unexpected error message: %q", err.Error())
+	}
+}
+
+// TestDoMultiError verifies that WithMultiError makes errors of earlier
+// attempts reachable and that AllAttemptErrors returns all of them.
+func TestDoMultiError(t *testing.T) {
+	t.Parallel()
+	rc := NewRetry(WithAttempts(3), WithDelay(time.Millisecond), WithMultiError())
+	attemptErrs := []error{errors.New("first"), errors.New("second"), errors.New("third")}
+
+	calls := 0
+	_, err := Do(context.Background(), rc, func() (string, error) {
+		err := attemptErrs[calls]
+		calls++
+		return "", err
+	})
+
+	if !errors.Is(err, ErrExhausted) {
+		t.Fatalf("expected ErrExhausted, got %v", err)
+	}
+	for _, attemptErr := range attemptErrs {
+		if !errors.Is(err, attemptErr) {
+			t.Errorf("expected %v to be reachable with errors.Is", attemptErr)
+		}
+	}
+
+	wrapped := fmt.Errorf("sync failed: %w", err)
+	if got := AllAttemptErrors(wrapped); !slices.Equal(got, attemptErrs) {
+		t.Errorf("expected all attempt errors %v, got %v", attemptErrs, got)
+	}
+
+	if err.Error() != "all attempts failed, the errors:\nfirst\nsecond\nthird" {
+		t.Errorf("unexpected error message: %q", err.Error())
+	}
+
+	if got := AllAttemptErrors(errors.Join(attemptErrs[0], attemptErrs[1])); !slices.Equal(got, attemptErrs[:2]) {
+		t.Errorf("expected the joined errors, got %v", got)
+	}
+	if got := AllAttemptErrors(attemptErrs[0]); got != nil {
+		t.Errorf("expected nil for a plain error, got %v", got)
+	}
+}
+
+// TestIsExhaustedOtherErrors verifies that exhaustion is reported by every
+// entry point while other failures are not reported as exhaustion.
+func TestIsExhaustedOtherErrors(t *testing.T) {
+	t.Parallel()
+	errFail := errors.New("fail")
+	fast := NewRetry(WithAttempts(2), WithDelay(time.Millisecond))
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	testCases := []struct {
+		name      string
+		run       func() error
+		exhausted bool
+	}{
+		{
+			name: "Do",
+			run: func() error {
+				_, err := Do(context.Background(), fast, func() (string, error) { return "", errFail })
+				return err
+			},
+			exhausted: true,
+		},
+		{
+			name: "DoVoid",
+			run: func() error {
+				return DoVoid(context.Background(), fast, func() error { return errFail })
+			},
+			exhausted: true,
+		},
+		{
+			name: "DoChain",
+			run: func() error {
+				_, err := DoChain(context.Background(), Chain(fast, fast), func() (string, error) { return "", errFail })
+				return err
+			},
+			exhausted: true,
+		},
+		{
+			name: "non-retryable",
+			run: func() error {
+				_, err := Do(context.Background(), fast, func() (string, error) { return "", NonRetryable(errFail) })
+				return err
+			},
+		},
+		{
+			name: "rejected by predicate",
+			run: func() error {
+				rc := NewRetry(WithRetryIf(func(error) bool { return false }))
+				_, err := Do(context.Background(), rc, func() (string, error) { return "", errFail })
+				return err
+			},
+		},
+		{
+			name: "context canceled",
+			run: func() error {
+				_, err := Do(canceled, fast, func() (string, error) { return "", errFail })
+				return err
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.run()
+
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if IsExhausted(err) != tc.exhausted || errors.Is(err, ErrExhausted) != tc.exhausted {
+				t.Errorf("expected exhausted %v, got %v", tc.exhausted, err)
+			}
+		})
+	}
+}
+
+// TestRecoverPanics verifies that panics are converted into retryable errors
+// and that the panic handler receives the exact recovered value.
+func TestRecoverPanics(t *testing.T) {
+	t.Parallel()
+	type panicValue struct{ code int }
+	value := &panicValue{code: 42}
+
+	var handled []any
+	var handledAttempts []int
+	rc := NewRetry(
+		WithAttempts(3),
+		WithDelay(time.Millisecond),
+		WithRecoverPanics(true),
+		WithPanicHandler(func(attempt int, recovered any) {
+			handledAttempts = append(handledAttempts, attempt)
+			handled = append(handled, recovered)
+		}),
+	)
+
+	calls := 0
+	result, err := Do(context.Background(), rc, func() (string, error) {
+		calls++
+		if calls < 3 {
+			panic(value)
+		}
+		return "ok", nil
+	})
+	if err != nil || result != "ok" {
+		t.Fatalf("expected success after panics, got %q, %v", result, err)
+	}
+
+	if len(handled) != 2 || handled[0] != value || handled[1] != value {
+		t.Errorf("expected the handler to receive the panic value twice, got %v", handled)
+	}
+	if !slices.Equal(handledAttempts, []int{1, 2}) {
+		t.Errorf("expected handler attempts [1 2], got %v", handledAttempts)
+	}
+}
+
+// TestRecoverPanicsExhausted verifies that the last error wraps ErrPanicked
+// when every attempt panics, also with a maximum attempt duration.
+func TestRecoverPanicsExhausted(t *testing.T) {
+	t.Parallel()
+	rc := NewRetry(
+		WithAttempts(2),
+		WithDelay(time.Millisecond),
+		WithMaxAttemptDuration(time.Second),
+		WithRecoverPanics(true),
+	)
+
+	_, err := Do(context.Background(), rc, func() (int, error) {
+		panic("boom")
+	})
+	if !errors.Is(err, ErrPanicked) || !errors.Is(err, ErrExhausted) {
+		t.Fatalf("expected exhausted error wrapping ErrPanicked, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "boom") {
+		t.Errorf("expected error to contain the panic value, got %v", err)
+	}
+}
+
+// TestCostBudget verifies that the loop stops once the cumulative attempt
+// cost would exceed the budget, even though attempts remain.
+func TestCostBudget(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name          string
+		budget        float64
+		expectedCalls int
+		expectBudget  bool
+	}{
+		{"budget allows 3 attempts", 6, 3, true},
+		{"budget exceeded by first attempt", 0.5, 0, true},
+		{"budget not reached", 100, 5, false},
+		{"no budget", 0, 5, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rc := NewRetry(
+				WithAttempts(5),
+				WithDelay(time.Millisecond),
+				WithAttemptCost(func(attempt int) float64 { return float64(attempt) }),
+				WithTotalCostBudget(tc.budget),
+			)
+
+			calls := 0
+			_, err := Do(context.Background(), rc, func() (int, error) {
+				calls++
+				return 0, errors.New("fail")
+			})
+
+			if calls != tc.expectedCalls {
+				t.Errorf("expected %d calls, got %d", tc.expectedCalls, calls)
+			}
+			if errors.Is(err, ErrCostBudgetExceeded) != tc.expectBudget {
+				t.Errorf("expected ErrCostBudgetExceeded %t, got %v", tc.expectBudget, err)
+			}
+		})
+	}
+}
+
+// TestWithFailFast verifies that retries are skipped only when the first
+// attempt failed slower than the base delay.
+func TestWithFailFast(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name          string
+		firstDuration time.Duration
+		expectedCalls int
+	}{
+		{"slow first attempt", 30 * time.Millisecond, 1},
+		{"fast first attempt", 0, 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rc := NewRetry(WithAttempts(3), WithDelay(10*time.Millisecond), WithFailFast())
+
+			calls := 0
+			_, err := Do(context.Background(), rc, func() (int, error) {
+				calls++
+				if calls == 1 {
+					time.Sleep(tc.firstDuration)
+				}
+				return 0, errors.New("fail")
+			})
+
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if calls != tc.expectedCalls {
+				t.Errorf("expected %d calls, got %d", tc.expectedCalls, calls)
+			}
+		})
+	}
+}
+
+// TestWithMaxElapsedTime verifies that Do stops before a delay that would
+// exceed the time budget, regardless of the remaining attempts.
+func TestWithMaxElapsedTime(t *testing.T) {
+	t.Parallel()
+	errFail := errors.New("fail")
+
+	testCases := []struct {
+		name          string
+		maxElapsed    time.Duration
+		expectedCalls int
+		err           error
+	}{
+		{name: "budget ends the loop", maxElapsed: 30 * time.Millisecond, expectedCalls: 2, err: ErrMaxElapsedTimeExceeded},
+		{name: "attempts end the loop", maxElapsed: time.Minute, expectedCalls: 10, err: ErrExhausted},
+		{name: "disabled", maxElapsed: 0, expectedCalls: 10, err: ErrExhausted},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rc := NewRetry(WithAttempts(10), WithDelay(20*time.Millisecond), WithMaxElapsedTime(tc.maxElapsed))
+
+			calls := 0
+			start := time.Now()
+			_, err := Do(context.Background(), rc, func() (int, error) {
+				calls++
+				return 0, errFail
+			})
+
+			if !errors.Is(err, tc.err) || !errors.Is(err, errFail) {
+				t.Errorf("expected %v wrapping the last error, got %v", tc.err, err)
+			}
+			if calls != tc.expectedCalls {
+				t.Errorf("expected %d calls, got %d", tc.expectedCalls, calls)
+			}
+			if tc.maxElapsed > 0 && time.Since(start) > tc.maxElapsed {
+				t.Errorf("expected Do to return within %v, took %v", tc.maxElapsed, time.Since(start))
+			}
+		})
+	}
+}
+
+// TestWithDeadlineMargin verifies that the loop stops before the context
+// deadline once the remaining time falls within the margin.
+func TestWithDeadlineMargin(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	rc := NewRetry(WithAttempts(100), WithDelay(time.Millisecond), WithDeadlineMargin(100*time.Millisecond))
+
+	calls := 0
+	_, err := Do(ctx, rc, func() (int, error) {
+		calls++
+		time.Sleep(30 * time.Millisecond)
+		return 0, errors.New("slow failure")
+	})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if ctx.Err() != nil {
+		t.Error("expected the loop to stop before the context deadline")
+	}
+	if calls == 0 || calls > 4 {
+		t.Errorf("expected 1 to 4 calls before reaching the margin, got %d", calls)
+	}
+}
+
+// TestDoDelayBeyondDeadline verifies that Do returns right away instead of
+// sleeping past the context deadline, and still sleeps delays that fit.
+func TestDoDelayBeyondDeadline(t *testing.T) {
+	t.Parallel()
+	errFail := errors.New("fail")
+
+	testCases := []struct {
+		name          string
+		delay         time.Duration
+		margin        time.Duration
+		expectedCalls int
+	}{
+		{name: "delay beyond deadline", delay: 10 * time.Second, expectedCalls: 1},
+		{name: "delay into margin", delay: 20 * time.Millisecond, margin: 9990 * time.Millisecond, expectedCalls: 1},
+		{name: "delay within deadline", delay: time.Millisecond, expectedCalls: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			rc := NewRetry(WithDelay(tc.delay), WithMaxDelay(tc.delay), WithDeadlineMargin(tc.margin))
+
+			calls := 0
+			start := time.Now()
+			_, err := Do(ctx, rc, func() (int, error) {
+				calls++
+				return 0, errFail
+			})
+
+			if !errors.Is(err, errFail) {
+				t.Errorf("expected the last attempt error, got %v", err)
+			}
+			if beyond := tc.expectedCalls == 1; errors.Is(err, context.DeadlineExceeded) != beyond {
+				t.Errorf("expected deadline error %v, got %v", beyond, err)
+			}
+			if calls != tc.expectedCalls {
+				t.Errorf("expected %d calls, got %d", tc.expectedCalls, calls)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("expected Do to return without waiting, took %v", elapsed)
+			}
+		})
+	}
+}
+
+// TestWithSleepFunc verifies that delays are passed to the sleep function
+// instead of waiting for real time.
+func TestWithSleepFunc(t *testing.T) {
+	t.Parallel()
+	var slept []time.Duration
+	rc := NewRetry(
+		WithAttempts(3),
+		WithDelay(time.Hour),
+		WithMaxDelay(time.Hour),
+		WithSleepFunc(func(d time.Duration) { slept = append(slept, d) }),
+	)
+
+	start := time.Now()
+	_, err := Do(context.Background(), rc, func() (int, error) {
+		return 0, errors.New("fail")
+	})
+	if !errors.Is(err, ErrExhausted) {
+		t.Fatalf("expected ErrExhausted, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("expected no real sleep")
+	}
+	if !slices.Equal(slept, []time.Duration{time.Hour, time.Hour}) {
+		t.Errorf("expected two sleeps of 1h, got %v", slept)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rc = NewRetry(WithAttempts(3), WithSleepFunc(func(time.Duration) { cancel() }))
+	_, err = Do(ctx, rc, func() (int, error) {
+		return 0, errors.New("fail")
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected cancellation during sleep to be detected, got %v", err)
+	}
+}
+
+// TestExhaustedErrorTotalAttempts verifies that TotalAttempts counts the
+// calls of the operation: attempts skipped by the filter are not counted,
+// soft retries are.
+func TestExhaustedErrorTotalAttempts(t *testing.T) {
+	t.Parallel()
+	rc := NewRetry(
+		WithAttempts(5),
+		WithDelay(time.Millisecond),
+		WithAttemptFilter(func(attempt int, _ error) bool { return attempt%2 == 1 }),
+	)
+
+	calls := 0
+	_, err := Do(context.Background(), rc, func() (int, error) {
+		calls++
+		if calls == 1 {
+			return 0, SoftRetry(errors.New("not ready"))
+		}
+		return 0, errors.New("fail")
+	})
+
+	var exhausted *ExhaustedError
+	if !errors.As(err, &exhausted) {
+		t.Fatalf("expected *ExhaustedError, got %v", err)
+	}
+	// One soft retry plus attempts 1, 3 and 5.
+	if calls != 4 || exhausted.TotalAttempts() != calls {
+		t.Errorf("expected 4 total attempts, got %d after %d calls", exhausted.TotalAttempts(), calls)
+	}
+}
+
+// TestWithAttemptFilter verifies that rejected attempts are skipped without
+// a delay while consuming the budget.
+func TestWithAttemptFilter(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name          string
+		filter        func(attempt int, err error) bool
+		expectedCalls []int
+		expectedDelay int
+	}{
+		{
+			name:          "odd attempts only",
+			filter:        func(attempt int, _ error) bool { return attempt%2 == 1 },
+			expectedCalls: []int{1, 3, 5},
+			expectedDelay: 2,
+		},
+		{
+			name:          "all remaining skipped",
+			filter:        func(int, error) bool { return false },
+			expectedCalls: []int{1},
+			expectedDelay: 0,
+		},
+		{
+			name:          "all allowed",
+			filter:        func(int, error) bool { return true },
+			expectedCalls: []int{1, 2, 3, 4, 5},
+			expectedDelay: 4,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			delays := 0
+			rc := NewRetry(
+				WithAttempts(5),
+				WithDelay(time.Millisecond),
+				WithAttemptFilter(tc.filter),
+				WithOnRetry(func(int, error, time.Duration) { delays++ }),
+			)
+
+			trace, _, err := DoWithTrace(context.Background(), rc, func() (int, error) {
+				return 0, errors.New("fail")
+			})
+
+			var calls []int
+			for _, e := range trace.Events() {
+				if e.Kind == EventAttemptStart {
+					calls = append(calls, e.Attempt)
+				}
+			}
+
+			if !errors.Is(err, ErrExhausted) {
+				t.Errorf("expected ErrExhausted, got %v", err)
+			}
+			if !slices.Equal(calls, tc.expectedCalls) {
+				t.Errorf("expected attempts %v, got %v", tc.expectedCalls, calls)
+			}
+			if delays != tc.expectedDelay {
+				t.Errorf("expected %d delays, got %d", tc.expectedDelay, delays)
+			}
+		})
+	}
+}
+
+// TestWithAttemptFilterUnlimited verifies that a filter rejecting every
+// attempt with unlimited attempts gives up after maxSkippedAttempts skips
+// instead of looping until the attempt counter overflows.
+func TestWithAttemptFilterUnlimited(t *testing.T) {
+	t.Parallel()
+	asked := 0
+	rc := NewRetry(
+		WithUnlimitedAttempts(),
+		WithDelay(time.Millisecond),
+		WithAttemptFilter(func(int, error) bool {
+			asked++
+			return false
+		}),
+	)
+
+	errFail := errors.New("fail")
+	calls := 0
+	_, err := Do(context.Background(), rc, func() (int, error) {
+		calls++
+		return 0, errFail
+	})
+
+	var exhausted *ExhaustedError
+	if !errors.As(err, &exhausted) || !errors.Is(err, errFail) {
+		t.Fatalf("expected *ExhaustedError wrapping the last error, got %v", err)
+	}
+	if calls != 1 || exhausted.TotalAttempts() != 1 {
+		t.Errorf("expected a single call, got %d calls and %d total attempts", calls, exhausted.TotalAttempts())
+	}
+	if asked != maxSkippedAttempts {
+		t.Errorf("expected the filter to be asked %d times, got %d", maxSkippedAttempts, asked)
+	}
+}
+
+// TestNoAttempts verifies that a budget without any attempt returns an
+// *ExhaustedError with ErrNoAttempts as the last error and never calls the
+// operation.
+func TestNoAttempts(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "zero attempts", opts: []Option{WithAttempts(0)}},
+		{name: "negative attempts", opts: []Option{WithAttempts(-1)}},
+		{name: "zero dynamic attempts", opts: []Option{WithDynamicAttempts(func() int { return 0 })}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			calls := 0
+			_, err := Do(context.Background(), NewRetry(tc.opts...), func() (int, error) {
+				calls++
+				return 0, nil
+			})
+
+			var exhausted *ExhaustedError
+			if !errors.As(err, &exhausted) || !errors.Is(err, ErrNoAttempts) {
+				t.Fatalf("expected *ExhaustedError wrapping ErrNoAttempts, got %v", err)
+			}
+			if calls != 0 || exhausted.TotalAttempts() != 0 {
+				t.Errorf("expected no calls, got %d calls and %d total attempts", calls, exhausted.TotalAttempts())
+			}
+			if want := "all attempts failed, the last error: no attempt was made"; err.Error() != want {
+				t.Errorf("expected %q, got %q", want, err.Error())
+			}
+		})
+	}
+}
+
+// TestWithMutex verifies that hooks and stateful delay strategies of a
+// shared configuration are serialized across concurrent Do calls; the test
+// relies on -race to detect unsynchronized access.
+func TestWithMutex(t *testing.T) {
+	t.Parallel()
+	retries := 0
+	calculated := 0
+	rc := NewRetry(
+		WithMutex(),
+		WithAttempts(3),
+		WithDelay(time.Millisecond),
+		WithDelayType(func(_ int, baseDelay, _ time.Duration) time.Duration {
+			calculated++
+			return baseDelay
+		}),
+		WithOnRetry(func(int, error, time.Duration) { retries++ }),
+	)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = Do(context.Background(), rc, func() (int, error) {
+				return 0, errors.New("fail")
+			})
+		}()
+	}
+	wg.Wait()
+
+	if retries != 40 || calculated != 40 {
+		t.Errorf("expected 40 retries and delay calculations, got %d and %d", retries, calculated)
+	}
+}
+
+// TestDoPerAttemptLogger verifies that messages about each attempt go to
+// the logger selected for it, falling back to the global logger on nil.
+func TestDoPerAttemptLogger(t *testing.T) {
+	t.Parallel()
+	global := &recordingLogger{}
+	alerts := &recordingLogger{}
+	rc := NewRetry(
+		WithAttempts(3),
+		WithDelay(time.Millisecond),
+		WithLogger(global),
+		WithPerAttemptLogger(func(attempt int) Logger {
+			if attempt >= 2 {
+				return alerts
+			}
+			return nil
+		}),
+	)
+
+	_, err := Do(context.Background(), rc, func() (int, error) {
+		return 0, errors.New("fail")
+	})
+	if !errors.Is(err, ErrExhausted) {
+		t.Fatalf("expected ErrExhausted, got %v", err)
+	}
+
+	if len(global.lines) != 1 || !strings.HasPrefix(global.lines[0], "Attempt 1 failed") {
+		t.Errorf("expected only attempt 1 in the global logger, got %v", global.lines)
+	}
+	if len(alerts.lines) != 2 ||
+		!strings.HasPrefix(alerts.lines[0], "Attempt 2 failed") ||
+		!strings.HasPrefix(alerts.lines[1], "All 3 attempts failed") {
+		t.Errorf("expected attempts 2 and 3 in the alert logger, got %v", alerts.lines)
+	}
+}
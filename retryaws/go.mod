@@ -0,0 +1,9 @@
+module github.com/1amDudman/try-again-go/retryaws
+
+go 1.24.4
+
+require (
+	github.com/1amDudman/try-again-go v0.1.0
+	github.com/aws/aws-sdk-go-v2 v1.47.1
+	github.com/aws/smithy-go v1.28.2
+)
@@ -0,0 +1,36 @@
+// Package retryaws provides retry classification for operations on any AWS
+// service made with the AWS SDK for Go v2. It lives in its own module so
+// that the AWS dependencies are only pulled in by users who need them.
+package retryaws
+
+import (
+	retry "github.com/1amDudman/try-again-go"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsretry "github.com/aws/aws-sdk-go-v2/aws/retry"
+)
+
+// WithAWSRetryPredicate returns a retry option that classifies errors the
+// same way the standard retryer of the AWS SDK for Go v2 does, using the
+// checks in awsretry.DefaultRetryables. This lets operations outside the
+// SDK, or SDK calls wrapped in an outer retry loop, share the SDK's notion
+// of a retryable error.
+//
+// Only errors the SDK positively identifies as retryable are retried, such
+// as throttling, connection and 5xx errors; unknown errors stop the retry
+// loop. Unlike awss3.WithS3Retryable it applies to every AWS service.
+//
+// Example:
+//
+//	rc := retry.NewRetry(retryaws.WithAWSRetryPredicate())
+//	out, err := retry.Do(ctx, rc, func() (*dynamodb.GetItemOutput, error) {
+//	    return client.GetItem(ctx, input)
+//	})
+func WithAWSRetryPredicate() retry.Option {
+	return retry.WithRetryIf(IsAWSRetryable)
+}
+
+// IsAWSRetryable reports whether the standard retryer of the AWS SDK for
+// Go v2 would retry err.
+func IsAWSRetryable(err error) bool {
+	return awsretry.IsErrorRetryables(awsretry.DefaultRetryables).IsErrorRetryable(err) == aws.TrueTernary
+}
@@ -0,0 +1,69 @@
+package retryaws
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	retry "github.com/1amDudman/try-again-go"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// responseError returns an error for an HTTP response with the given status.
+func responseError(status int) error {
+	return &smithyhttp.ResponseError{
+		Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+		Err:      errors.New("response error"),
+	}
+}
+
+// TestIsAWSRetryable verifies that the classification matches the standard
+// retryer of the AWS SDK.
+func TestIsAWSRetryable(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"request send error", &smithyhttp.RequestSendError{Err: errors.New("dial tcp")}, true},
+		{"throttling", &smithy.GenericAPIError{Code: "ThrottlingException"}, true},
+		{"request timeout", &smithy.GenericAPIError{Code: "RequestTimeout"}, true},
+		{"response 503", responseError(http.StatusServiceUnavailable), true},
+		{"access denied", &smithy.GenericAPIError{Code: "AccessDenied"}, false},
+		{"response 400", responseError(http.StatusBadRequest), false},
+		{"context canceled", context.Canceled, false},
+		{"other error", errors.New("some error"), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := IsAWSRetryable(tc.err); got != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestWithAWSRetryPredicate verifies that unknown errors stop the retry loop.
+func TestWithAWSRetryPredicate(t *testing.T) {
+	t.Parallel()
+	rc := retry.NewRetry(retry.WithAttempts(3), retry.WithDelay(time.Millisecond), WithAWSRetryPredicate())
+
+	calls := 0
+	_, err := retry.Do(context.Background(), rc, func() (int, error) {
+		calls++
+		if calls == 1 {
+			return 0, &smithy.GenericAPIError{Code: "ThrottlingException"}
+		}
+		return 0, errors.New("unknown")
+	})
+	if err == nil || calls != 2 {
+		t.Errorf("expected to stop after 2 calls with an error, got %d calls, %v", calls, err)
+	}
+}
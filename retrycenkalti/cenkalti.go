@@ -0,0 +1,127 @@
+// Package retrycenkalti eases the migration from github.com/cenkalti/backoff
+// by converting its backoff policies to RetryConfigs and back. It lives in
+// its own module so that the backoff dependency is only pulled in by users
+// who need it.
+//
+// The two libraries end a retry loop differently. cenkalti/backoff stops
+// when NextBackOff returns backoff.Stop, e.g. after backoff.WithMaxRetries
+// or MaxElapsedTime, and when the operation returns a *backoff.PermanentError.
+// This library stops after a fixed number of attempts and when the operation
+// returns an error wrapped with retry.NonRetryable. A delay strategy cannot
+// end the loop, so:
+//   - a backoff.Stop returned by a converted BackOff is treated as the
+//     maximum delay, and the number of attempts must be set with
+//     retry.WithAttempts;
+//   - MaxElapsedTime is not converted; bound the loop with
+//     context.WithTimeout instead;
+//   - operations must return retry.NonRetryable(err) instead of
+//     backoff.Permanent(err).
+package retrycenkalti
+
+import (
+	"sync"
+	"time"
+
+	retry "github.com/1amDudman/try-again-go"
+	"github.com/cenkalti/backoff/v4"
+)
+
+// FromCenkaltiBackoff creates a RetryConfig whose delays are taken from b.
+// The BackOff is reset before the first retry of every Do call and then
+// asked for one delay per retry, so its own state, such as the growing
+// interval of backoff.ExponentialBackOff, drives the delays. The extra
+// options are applied afterwards; set the number of attempts with
+// retry.WithAttempts, see the package documentation.
+//
+// BackOff implementations are stateful, so concurrent Do calls with the
+// returned config interleave their delays. Create one config per retry loop
+// when the delays matter.
+//
+// Example:
+//
+//	rc := retrycenkalti.FromCenkaltiBackoff(
+//	    backoff.NewConstantBackOff(500*time.Millisecond),
+//	    retry.WithAttempts(5),
+//	)
+func FromCenkaltiBackoff(b backoff.BackOff, extra ...retry.Option) *retry.RetryConfig {
+	var mu sync.Mutex
+
+	delayType := func(attempt int, _, maxDelay time.Duration) time.Duration {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if attempt == 1 {
+			b.Reset()
+		}
+
+		delay := b.NextBackOff()
+		if delay == backoff.Stop {
+			return maxDelay
+		}
+
+		return delay
+	}
+
+	return retry.NewRetry(append([]retry.Option{retry.WithDelayType(delayType)}, extra...)...)
+}
+
+// FromCenkaltiPolicy creates a RetryConfig equivalent to the exponential
+// backoff policy p. cenkalti/backoff has no separate policy type; the
+// fields of backoff.ExponentialBackOff are its configuration:
+//   - InitialInterval: retry.WithDelay
+//   - MaxInterval: retry.WithMaxDelay
+//   - Multiplier: retry.ExpBackoffWithBase
+//   - RandomizationFactor: retry.WithExpJitter
+//
+// cenkalti/backoff randomizes each delay by up to RandomizationFactor in
+// both directions, while WithExpJitter only adds to it, so delays are on
+// average slightly longer. MaxElapsedTime and Clock are not converted. The
+// extra options are applied after the mapped values.
+//
+// Example:
+//
+//	rc := retrycenkalti.FromCenkaltiPolicy(backoff.NewExponentialBackOff(),
+//	    retry.WithAttempts(5),
+//	)
+func FromCenkaltiPolicy(p *backoff.ExponentialBackOff, extra ...retry.Option) *retry.RetryConfig {
+	opts := []retry.Option{
+		retry.WithDelay(p.InitialInterval),
+		retry.WithMaxDelay(p.MaxInterval),
+		retry.WithDelayType(retry.ExpBackoffWithBase(p.Multiplier, retry.WithExpJitter(p.RandomizationFactor))),
+	}
+
+	return retry.NewRetry(append(opts, extra...)...)
+}
+
+// cenkaltiBackOff implements backoff.BackOff with the delays of a
+// RetryConfig.
+type cenkaltiBackOff struct {
+	b *retry.Backoff // Yields the delays
+}
+
+// ToCenkaltiBackoff returns a backoff.BackOff that yields the same delays
+// Do would sleep between the attempts of rc and returns backoff.Stop once
+// the configured number of attempts has been made. Like retry.Backoff it is
+// stateful and not safe for concurrent use.
+//
+// Example:
+//
+//	err := backoff.Retry(op, retrycenkalti.ToCenkaltiBackoff(rc))
+func ToCenkaltiBackoff(rc *retry.RetryConfig) backoff.BackOff {
+	return &cenkaltiBackOff{b: retry.NewBackoff(rc)}
+}
+
+// NextBackOff returns the next delay or backoff.Stop.
+func (c *cenkaltiBackOff) NextBackOff() time.Duration {
+	delay, ok := c.b.NextDuration()
+	if !ok {
+		return backoff.Stop
+	}
+
+	return delay
+}
+
+// Reset restarts the sequence from the first attempt.
+func (c *cenkaltiBackOff) Reset() {
+	c.b.Reset()
+}
@@ -0,0 +1,124 @@
+package retrycenkalti
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	retry "github.com/1amDudman/try-again-go"
+	"github.com/cenkalti/backoff/v4"
+)
+
+// sequenceBackOff yields a fixed sequence of delays followed by
+// backoff.Stop and counts resets.
+type sequenceBackOff struct {
+	delays []time.Duration
+	next   int
+	resets int
+}
+
+func (s *sequenceBackOff) NextBackOff() time.Duration {
+	if s.next >= len(s.delays) {
+		return backoff.Stop
+	}
+	s.next++
+	return s.delays[s.next-1]
+}
+
+func (s *sequenceBackOff) Reset() {
+	s.next = 0
+	s.resets++
+}
+
+// TestFromCenkaltiBackoff verifies that Do sleeps the delays of the BackOff,
+// resets it for every call and uses the maximum delay after backoff.Stop.
+func TestFromCenkaltiBackoff(t *testing.T) {
+	t.Parallel()
+	b := &sequenceBackOff{delays: []time.Duration{time.Millisecond, 3 * time.Millisecond}}
+
+	var delays []time.Duration
+	rc := FromCenkaltiBackoff(b,
+		retry.WithAttempts(4),
+		retry.WithDelay(time.Millisecond),
+		retry.WithMaxDelay(5*time.Millisecond),
+		retry.WithOnRetry(func(_ int, _ error, delay time.Duration) {
+			delays = append(delays, delay)
+		}),
+	)
+
+	errFail := errors.New("fail")
+	for range 2 {
+		_, err := retry.Do(context.Background(), rc, func() (int, error) {
+			return 0, errFail
+		})
+		if !errors.Is(err, errFail) {
+			t.Fatalf("expected %v, got %v", errFail, err)
+		}
+	}
+
+	want := []time.Duration{time.Millisecond, 3 * time.Millisecond, 5 * time.Millisecond}
+	if !slices.Equal(delays, append(want, want...)) {
+		t.Errorf("expected delays %v twice, got %v", want, delays)
+	}
+	if b.resets != 2 {
+		t.Errorf("expected 2 resets, got %d", b.resets)
+	}
+}
+
+// TestFromCenkaltiPolicy verifies that the fields of an ExponentialBackOff
+// are mapped to the configuration.
+func TestFromCenkaltiPolicy(t *testing.T) {
+	t.Parallel()
+	p := backoff.NewExponentialBackOff()
+	p.InitialInterval = 100 * time.Millisecond
+	p.MaxInterval = time.Second
+	p.Multiplier = 3
+	p.RandomizationFactor = 0
+
+	rc := FromCenkaltiPolicy(p, retry.WithAttempts(5))
+
+	if got := rc.String(); !strings.HasPrefix(got, "attempts=5 baseDelay=100ms maxDelay=1s ") {
+		t.Errorf("unexpected config %q", got)
+	}
+
+	want := []time.Duration{100 * time.Millisecond, 300 * time.Millisecond, 900 * time.Millisecond, time.Second}
+	for i, delay := range want {
+		if got := retry.DelayForAttempt(rc, i+1); got != delay {
+			t.Errorf("attempt %d: expected delay %v, got %v", i+1, delay, got)
+		}
+	}
+}
+
+// TestToCenkaltiBackoff verifies that backoff.Retry makes the configured
+// number of attempts with the delays of the RetryConfig.
+func TestToCenkaltiBackoff(t *testing.T) {
+	t.Parallel()
+	rc := retry.NewRetry(
+		retry.WithAttempts(3),
+		retry.WithDelay(time.Millisecond),
+		retry.WithMaxDelay(time.Second),
+		retry.WithDelayType(retry.ExpBackoffWithBase(2, retry.WithExpJitter(0))),
+	)
+
+	var delays []time.Duration
+	calls := 0
+	err := backoff.RetryNotify(func() error {
+		calls++
+		return errors.New("fail")
+	}, ToCenkaltiBackoff(rc), func(_ error, delay time.Duration) {
+		delays = append(delays, delay)
+	})
+
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+	if !slices.Equal(delays, []time.Duration{time.Millisecond, 2 * time.Millisecond}) {
+		t.Errorf("expected delays [1ms 2ms], got %v", delays)
+	}
+}
@@ -0,0 +1,8 @@
+module github.com/1amDudman/try-again-go/retrycenkalti
+
+go 1.24.4
+
+require (
+	github.com/1amDudman/try-again-go v0.1.0
+	github.com/cenkalti/backoff/v4 v4.3.0
+)
@@ -0,0 +1,175 @@
+// Package retryconsul reads retry policies from the Consul key-value store
+// using github.com/hashicorp/consul/api. It lives in its own module so that
+// the Consul dependencies are only pulled in by users who need them.
+package retryconsul
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	retry "github.com/1amDudman/try-again-go"
+	"github.com/hashicorp/consul/api"
+)
+
+// ErrKeyNotFound is returned when the Consul key does not exist.
+var ErrKeyNotFound = errors.New("consul key not found")
+
+// strategies maps the values of the strategy field to delay strategies.
+var strategies = map[string]func() retry.DelayTypeFunc{
+	"fixed":       retry.FixedDelay,
+	"exponential": retry.ExpBackoffWithJitter,
+}
+
+// policy is the JSON document stored in Consul.
+type policy struct {
+	Attempts  *int   `json:"attempts"`
+	BaseDelay string `json:"base_delay"`
+	MaxDelay  string `json:"max_delay"`
+	Strategy  string `json:"strategy"`
+}
+
+// NewRetryFromConsulKV creates a RetryConfig from the JSON document stored
+// under key. Fields that are not set keep the defaults of retry.NewRetry:
+//   - attempts: number of attempts, e.g. 5
+//   - base_delay: base delay, e.g. "200ms"
+//   - max_delay: maximum delay, e.g. "5s"
+//   - strategy: "fixed" or "exponential"
+//
+// The extra options are applied after the stored values, so they can add
+// settings that cannot be expressed in JSON, such as a logger. The
+// resulting configuration is validated; errors wrap retry.ErrInvalidConfig.
+// A missing key is reported with ErrKeyNotFound.
+//
+// Example:
+//
+//	// consul kv put config/retry '{"attempts": 5, "base_delay": "200ms"}'
+//	rc, err := retryconsul.NewRetryFromConsulKV(ctx, client, "config/retry",
+//	    retry.WithLogger(log.Default()),
+//	)
+func NewRetryFromConsulKV(ctx context.Context, client *api.Client, key string, extra ...retry.Option) (*retry.RetryConfig, error) {
+	rc, _, err := load(ctx, client, key, 0, extra)
+	return rc, err
+}
+
+// Policy holds a RetryConfig that is kept up to date with a Consul key by
+// WatchAndUpdate. It is safe for concurrent use.
+type Policy struct {
+	current atomic.Pointer[retry.RetryConfig]
+}
+
+// Current returns the most recent valid configuration. Retrieve it for
+// every operation instead of keeping it, so that updates take effect.
+func (p *Policy) Current() *retry.RetryConfig {
+	return p.current.Load()
+}
+
+// WatchAndUpdate loads the configuration stored under key like
+// NewRetryFromConsulKV and keeps it up to date in the background using
+// Consul blocking queries until ctx is canceled. Updates that cannot be
+// loaded, such as invalid JSON or a deleted key, are skipped and the last
+// valid configuration stays in effect. The initial load must succeed.
+//
+// Example:
+//
+//	policy, err := retryconsul.WatchAndUpdate(ctx, client, "config/retry")
+//	if err != nil {
+//	    return err
+//	}
+//	result, err := retry.Do(ctx, policy.Current(), fetch)
+func WatchAndUpdate(ctx context.Context, client *api.Client, key string, extra ...retry.Option) (*Policy, error) {
+	rc, index, err := load(ctx, client, key, 0, extra)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Policy{}
+	p.current.Store(rc)
+
+	go p.watch(ctx, client, key, index, extra)
+
+	return p, nil
+}
+
+// watch updates p whenever the key changes after index.
+func (p *Policy) watch(ctx context.Context, client *api.Client, key string, index uint64, extra []retry.Option) {
+	for ctx.Err() == nil {
+		rc, newIndex, err := load(ctx, client, key, index, extra)
+		if err != nil && newIndex == 0 {
+			// The query itself failed, e.g. Consul is unreachable.
+			// Back off briefly instead of hammering the agent.
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		// Consul indexes may go backwards, e.g. after a snapshot restore,
+		// in which case the watch starts over.
+		if newIndex < index {
+			newIndex = 0
+		}
+		if err == nil && newIndex != index {
+			p.current.Store(rc)
+		}
+		index = newIndex
+	}
+}
+
+// load reads and parses key. With a non-zero index it blocks until the key
+// changes after index or the wait time of Consul elapses. The returned index
+// is the one reported by Consul, or 0 if the query failed.
+func load(ctx context.Context, client *api.Client, key string, index uint64, extra []retry.Option) (*retry.RetryConfig, uint64, error) {
+	opts := (&api.QueryOptions{WaitIndex: index}).WithContext(ctx)
+	pair, meta, err := client.KV().Get(key, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read consul key %q: %w", key, err)
+	}
+	if pair == nil {
+		return nil, meta.LastIndex, fmt.Errorf("%w: %q", ErrKeyNotFound, key)
+	}
+
+	rc, err := parse(pair.Value, key, extra)
+	return rc, meta.LastIndex, err
+}
+
+// parse creates a RetryConfig from the JSON document data stored under key.
+func parse(data []byte, key string, extra []retry.Option) (*retry.RetryConfig, error) {
+	var p policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("%w: parse consul key %q: %v", retry.ErrInvalidConfig, key, err)
+	}
+
+	b := retry.NewRetryBuilder()
+
+	if p.Attempts != nil {
+		b.Attempts(*p.Attempts)
+	}
+	if p.BaseDelay != "" {
+		d, err := time.ParseDuration(p.BaseDelay)
+		if err != nil {
+			return nil, fmt.Errorf("%w: base_delay in consul key %q: %v", retry.ErrInvalidConfig, key, err)
+		}
+		b.Delay(d)
+	}
+	if p.MaxDelay != "" {
+		d, err := time.ParseDuration(p.MaxDelay)
+		if err != nil {
+			return nil, fmt.Errorf("%w: max_delay in consul key %q: %v", retry.ErrInvalidConfig, key, err)
+		}
+		b.MaxDelay(d)
+	}
+	if p.Strategy != "" {
+		strategy, ok := strategies[p.Strategy]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown strategy %q in consul key %q", retry.ErrInvalidConfig, p.Strategy, key)
+		}
+		b.DelayType(strategy())
+	}
+
+	return b.With(extra...).Build()
+}
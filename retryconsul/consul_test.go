@@ -0,0 +1,183 @@
+package retryconsul
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	retry "github.com/1amDudman/try-again-go"
+	"github.com/hashicorp/consul/api"
+)
+
+// fakeConsul serves a single key of the Consul KV HTTP API, including
+// blocking queries.
+type fakeConsul struct {
+	mu      sync.Mutex
+	value   []byte
+	index   uint64
+	changed chan struct{} // Closed and replaced on every update
+}
+
+func newFakeConsul(value string) *fakeConsul {
+	return &fakeConsul{value: []byte(value), index: 1, changed: make(chan struct{})}
+}
+
+// set stores a new value and wakes up blocking queries. A nil value
+// deletes the key.
+func (f *fakeConsul) set(value []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.value = value
+	f.index++
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func (f *fakeConsul) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	wait, _ := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64)
+
+	f.mu.Lock()
+	if wait > 0 && wait >= f.index {
+		changed := f.changed
+		f.mu.Unlock()
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		f.mu.Lock()
+	}
+	value, index := f.value, f.index
+	f.mu.Unlock()
+
+	w.Header().Set("X-Consul-Index", strconv.FormatUint(index, 10))
+	if value == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+	json.NewEncoder(w).Encode([]api.KVPair{{Key: key, Value: value, ModifyIndex: index}})
+}
+
+// newTestClient starts a fake Consul agent and returns a client for it.
+func newTestClient(t *testing.T, f *fakeConsul) *api.Client {
+	t.Helper()
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+
+	client, err := api.NewClient(&api.Config{Address: strings.TrimPrefix(server.URL, "http://")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// TestNewRetryFromConsulKV verifies that the JSON document is mapped to the
+// configuration and that invalid documents are rejected.
+func TestNewRetryFromConsulKV(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		value    string
+		expected string
+		err      error
+	}{
+		{
+			name:     "all fields",
+			value:    `{"attempts": 5, "base_delay": "200ms", "max_delay": "5s", "strategy": "exponential"}`,
+			expected: "attempts=5 baseDelay=200ms maxDelay=5s strategy=ExpBackoffWithJitter",
+		},
+		{
+			name:     "defaults",
+			value:    `{}`,
+			expected: "attempts=3 baseDelay=100ms maxDelay=1s strategy=FixedDelay",
+		},
+		{name: "invalid json", value: `{`, err: retry.ErrInvalidConfig},
+		{name: "invalid duration", value: `{"base_delay": "soon"}`, err: retry.ErrInvalidConfig},
+		{name: "unknown strategy", value: `{"strategy": "random"}`, err: retry.ErrInvalidConfig},
+		{name: "invalid attempts", value: `{"attempts": 0}`, err: retry.ErrInvalidConfig},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			client := newTestClient(t, newFakeConsul(tc.value))
+
+			rc, err := NewRetryFromConsulKV(context.Background(), client, "config/retry")
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("expected %v, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if !strings.HasPrefix(rc.String(), tc.expected) {
+				t.Errorf("expected config %q, got %q", tc.expected, rc.String())
+			}
+		})
+	}
+}
+
+// TestNewRetryFromConsulKVMissingKey verifies that a missing key is
+// reported with ErrKeyNotFound.
+func TestNewRetryFromConsulKVMissingKey(t *testing.T) {
+	t.Parallel()
+	f := newFakeConsul("")
+	f.value = nil
+
+	_, err := NewRetryFromConsulKV(context.Background(), newTestClient(t, f), "config/retry")
+	if !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+// TestWatchAndUpdate verifies that valid updates replace the current
+// configuration while invalid ones are skipped.
+func TestWatchAndUpdate(t *testing.T) {
+	t.Parallel()
+	f := newFakeConsul(`{"attempts": 2}`)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	policy, err := WatchAndUpdate(ctx, newTestClient(t, f), "config/retry")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	waitFor := func(prefix string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !strings.HasPrefix(policy.Current().String(), prefix) {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected config %q, got %q", prefix, policy.Current().String())
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	waitFor("attempts=2 ")
+
+	f.set([]byte(`{"attempts": 7}`))
+	waitFor("attempts=7 ")
+
+	f.set([]byte(`{"attempts": "many"}`))
+	time.Sleep(50 * time.Millisecond)
+	if got := policy.Current().String(); !strings.HasPrefix(got, "attempts=7 ") {
+		t.Errorf("expected the invalid update to be skipped, got %q", got)
+	}
+
+	f.set(nil)
+	f.set([]byte(`{"attempts": 4}`))
+	waitFor("attempts=4 ")
+}
@@ -0,0 +1,51 @@
+// Package retryelastic plugs retry policies into the official Elasticsearch
+// client github.com/elastic/go-elasticsearch. It lives in its own module so
+// that the Elasticsearch dependencies are only pulled in by users who need
+// them.
+package retryelastic
+
+import (
+	"time"
+
+	retry "github.com/1amDudman/try-again-go"
+	"github.com/elastic/go-elasticsearch/v8"
+)
+
+// The Elasticsearch client expects a plain func(attempt int) time.Duration
+// as RetryBackoff; Backoff must stay assignable to it.
+var _ = elasticsearch.Config{RetryBackoff: (*ElasticBackoffAdapter)(nil).Backoff}
+
+// ElasticBackoffAdapter makes the delays of a RetryConfig usable as the
+// RetryBackoff of the Elasticsearch client, so that Elasticsearch requests
+// back off like every other retried operation of a service. Only the delay
+// strategy is used: the number of retries is controlled by the MaxRetries
+// setting of the client, and the retried status codes by RetryOnStatus.
+// Use NewElasticBackoffAdapter() to create instances.
+type ElasticBackoffAdapter struct {
+	rc *retry.RetryConfig // Source of the delays
+}
+
+// NewElasticBackoffAdapter creates an ElasticBackoffAdapter for rc.
+//
+// Example:
+//
+//	backoff := retryelastic.NewElasticBackoffAdapter(retry.NewRetry(
+//	    retry.WithDelay(100*time.Millisecond),
+//	    retry.WithMaxDelay(5*time.Second),
+//	    retry.WithDelayType(retry.ExpBackoffWithJitter()),
+//	))
+//	es, err := elasticsearch.NewClient(elasticsearch.Config{
+//	    MaxRetries:   4,
+//	    RetryBackoff: backoff.Backoff,
+//	})
+func NewElasticBackoffAdapter(rc *retry.RetryConfig) *ElasticBackoffAdapter {
+	return &ElasticBackoffAdapter{rc: rc}
+}
+
+// Backoff returns the delay before the given retry, starting at 1, as
+// calculated by the delay strategy of the RetryConfig. Its signature
+// matches elasticsearch.Config.RetryBackoff and
+// elastictransport.WithRetryBackoff.
+func (a *ElasticBackoffAdapter) Backoff(attempt int) time.Duration {
+	return retry.DelayForAttempt(a.rc, attempt)
+}
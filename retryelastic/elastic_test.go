@@ -0,0 +1,63 @@
+package retryelastic
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	retry "github.com/1amDudman/try-again-go"
+	"github.com/elastic/go-elasticsearch/v8"
+)
+
+// TestElasticBackoffAdapter verifies that the Elasticsearch client backs off
+// with the delays of the RetryConfig.
+func TestElasticBackoffAdapter(t *testing.T) {
+	t.Parallel()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		if requests.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	adapter := NewElasticBackoffAdapter(retry.NewRetry(
+		retry.WithDelay(time.Millisecond),
+		retry.WithMaxDelay(time.Second),
+		retry.WithDelayType(retry.ExpBackoffWithBase(2, retry.WithExpJitter(0))),
+	))
+
+	var delays []time.Duration
+	es, err := elasticsearch.NewClient(elasticsearch.Config{
+		Addresses:  []string{server.URL},
+		MaxRetries: 3,
+		RetryBackoff: func(attempt int) time.Duration {
+			d := adapter.Backoff(attempt)
+			delays = append(delays, d)
+			return d
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := es.Ping(es.Ping.WithContext(context.Background()))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || requests.Load() != 3 {
+		t.Errorf("expected success on the third request, got status %d after %d requests", resp.StatusCode, requests.Load())
+	}
+	if !slices.Equal(delays, []time.Duration{time.Millisecond, 2 * time.Millisecond}) {
+		t.Errorf("expected delays [1ms 2ms], got %v", delays)
+	}
+}
@@ -0,0 +1,8 @@
+module github.com/1amDudman/try-again-go/retrygrpc
+
+go 1.24.4
+
+require (
+	github.com/1amDudman/try-again-go v0.1.0
+	google.golang.org/protobuf v1.36.12
+)
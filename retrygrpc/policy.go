@@ -0,0 +1,86 @@
+// Package retrygrpc creates retry policies from gRPC service configs. It
+// lives in its own module so that the protobuf dependencies are only pulled
+// in by users who need them.
+package retrygrpc
+
+import (
+	"fmt"
+	"time"
+
+	retry "github.com/1amDudman/try-again-go"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RetryPolicy mirrors the RetryPolicy message of grpc.service_config
+// (grpc/service_config/service_config.proto). grpc-go keeps its generated
+// type internal, so the fields are declared here with the same names and
+// protobuf types. Status codes are not mapped; use retry.WithRetryIf to
+// restrict retries to retryableStatusCodes.
+type RetryPolicy struct {
+	MaxAttempts       uint32               // Total number of attempts, including the first
+	InitialBackoff    *durationpb.Duration // Delay before the first retry
+	MaxBackoff        *durationpb.Duration // Upper bound of every delay
+	BackoffMultiplier float32              // Growth factor between consecutive delays
+}
+
+// NewRetryFromProtoRetryPolicy creates a RetryConfig equivalent to p:
+//   - maxAttempts: retry.WithAttempts
+//   - initialBackoff: retry.WithDelay
+//   - maxBackoff: retry.WithMaxDelay
+//   - backoffMultiplier: retry.ExpBackoffWithBase
+//
+// gRPC requires every field to be set, so a missing or non-positive value
+// is reported as an error wrapping retry.ErrInvalidConfig. The extra options
+// are applied after the mapped values, e.g. to add a logger.
+//
+// Example:
+//
+//	rc, err := retrygrpc.NewRetryFromProtoRetryPolicy(&retrygrpc.RetryPolicy{
+//	    MaxAttempts:       4,
+//	    InitialBackoff:    durationpb.New(100 * time.Millisecond),
+//	    MaxBackoff:        durationpb.New(time.Second),
+//	    BackoffMultiplier: 2,
+//	})
+func NewRetryFromProtoRetryPolicy(p *RetryPolicy, extra ...retry.Option) (*retry.RetryConfig, error) {
+	if p == nil {
+		return nil, fmt.Errorf("%w: nil retry policy", retry.ErrInvalidConfig)
+	}
+	if p.MaxAttempts < 2 {
+		return nil, fmt.Errorf("%w: maxAttempts must be at least 2, got %d", retry.ErrInvalidConfig, p.MaxAttempts)
+	}
+	if p.BackoffMultiplier <= 0 {
+		return nil, fmt.Errorf("%w: backoffMultiplier must be positive, got %g", retry.ErrInvalidConfig, p.BackoffMultiplier)
+	}
+
+	initial, err := positiveDuration("initialBackoff", p.InitialBackoff)
+	if err != nil {
+		return nil, err
+	}
+	maxBackoff, err := positiveDuration("maxBackoff", p.MaxBackoff)
+	if err != nil {
+		return nil, err
+	}
+
+	return retry.NewRetryBuilder().
+		Attempts(int(p.MaxAttempts)).
+		Delay(initial).
+		MaxDelay(maxBackoff).
+		DelayType(retry.ExpBackoffWithBase(float64(p.BackoffMultiplier))).
+		With(extra...).
+		Build()
+}
+
+// positiveDuration converts d and checks that it is set and positive.
+func positiveDuration(name string, d *durationpb.Duration) (time.Duration, error) {
+	if d == nil {
+		return 0, fmt.Errorf("%w: %s is not set", retry.ErrInvalidConfig, name)
+	}
+	if err := d.CheckValid(); err != nil {
+		return 0, fmt.Errorf("%w: %s: %v", retry.ErrInvalidConfig, name, err)
+	}
+	if d.AsDuration() <= 0 {
+		return 0, fmt.Errorf("%w: %s must be positive, got %v", retry.ErrInvalidConfig, name, d.AsDuration())
+	}
+
+	return d.AsDuration(), nil
+}
@@ -0,0 +1,85 @@
+package retrygrpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	retry "github.com/1amDudman/try-again-go"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// TestNewRetryFromProtoRetryPolicy verifies that the policy fields are
+// mapped to the attempts and delays of the configuration.
+func TestNewRetryFromProtoRetryPolicy(t *testing.T) {
+	t.Parallel()
+	var delays []time.Duration
+	rc, err := NewRetryFromProtoRetryPolicy(&RetryPolicy{
+		MaxAttempts:       4,
+		InitialBackoff:    durationpb.New(time.Millisecond),
+		MaxBackoff:        durationpb.New(3 * time.Millisecond),
+		BackoffMultiplier: 2,
+	}, retry.WithOnRetry(func(_ int, _ error, delay time.Duration) {
+		delays = append(delays, delay)
+	}))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	calls := 0
+	_, err = retry.Do(context.Background(), rc, func() (int, error) {
+		calls++
+		return 0, errors.New("unavailable")
+	})
+	if !errors.Is(err, retry.ErrExhausted) {
+		t.Fatalf("expected ErrExhausted, got %v", err)
+	}
+
+	if calls != 4 {
+		t.Errorf("expected 4 attempts, got %d", calls)
+	}
+	if len(delays) != 3 || delays[0] < time.Millisecond || delays[1] < 2*time.Millisecond || delays[2] != 3*time.Millisecond {
+		t.Errorf("expected delays of about [1ms 2ms 3ms], got %v", delays)
+	}
+}
+
+// TestNewRetryFromProtoRetryPolicyInvalid verifies that policies gRPC would
+// reject are reported as invalid configuration.
+func TestNewRetryFromProtoRetryPolicyInvalid(t *testing.T) {
+	t.Parallel()
+	valid := func() *RetryPolicy {
+		return &RetryPolicy{
+			MaxAttempts:       3,
+			InitialBackoff:    durationpb.New(time.Second),
+			MaxBackoff:        durationpb.New(time.Minute),
+			BackoffMultiplier: 2,
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		modify func(p *RetryPolicy)
+	}{
+		{name: "single attempt", modify: func(p *RetryPolicy) { p.MaxAttempts = 1 }},
+		{name: "missing initial backoff", modify: func(p *RetryPolicy) { p.InitialBackoff = nil }},
+		{name: "zero max backoff", modify: func(p *RetryPolicy) { p.MaxBackoff = durationpb.New(0) }},
+		{name: "negative multiplier", modify: func(p *RetryPolicy) { p.BackoffMultiplier = -1 }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			p := valid()
+			tc.modify(p)
+
+			if _, err := NewRetryFromProtoRetryPolicy(p); !errors.Is(err, retry.ErrInvalidConfig) {
+				t.Errorf("expected ErrInvalidConfig, got %v", err)
+			}
+		})
+	}
+
+	if _, err := NewRetryFromProtoRetryPolicy(nil); !errors.Is(err, retry.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for a nil policy, got %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package retryhttp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultMaxBodyBuffer is the default maximum size of a request body that
+// is buffered in memory to make it replayable.
+const defaultMaxBodyBuffer = 10 << 20 // 10 MiB
+
+// bufferedBody is an in-memory request body that can be rewound with Seek.
+type bufferedBody struct {
+	*bytes.Reader
+	err error // Error encountered while buffering, returned by Read
+}
+
+func (b *bufferedBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	if err == io.EOF && b.err != nil {
+		return n, b.err
+	}
+	return n, err
+}
+
+// Close implements io.Closer. The buffer stays readable after Close so the
+// body can be replayed on the next attempt.
+func (b *bufferedBody) Close() error { return nil }
+
+// NewBufferedRetryBody reads r into memory and returns a body that can be
+// replayed across retry attempts. The returned value also implements
+// io.Seeker; seeking to the start rewinds it. If r implements io.Closer it
+// is closed once fully read. An error encountered while reading r is
+// returned by Read after the buffered data.
+//
+// Client.Do uses this automatically for request bodies that cannot be
+// rewound, up to the limit set with WithMaxBodyBuffer.
+func NewBufferedRetryBody(r io.Reader) io.ReadCloser {
+	data, err := io.ReadAll(r)
+	if c, ok := r.(io.Closer); ok {
+		c.Close()
+	}
+
+	return &bufferedBody{Reader: bytes.NewReader(data), err: err}
+}
+
+// errBodyTooLarge is returned by makeReplayable when the body exceeds the limit.
+var errBodyTooLarge = errors.New("request body exceeds buffer limit")
+
+// makeReplayable prepares req for being sent multiple times. Requests that
+// already have GetBody, or no body at all, are returned unchanged. Other
+// bodies are buffered in memory up to limit bytes. If the body is larger,
+// the returned request carries the original body stream, cannot be
+// replayed, and errBodyTooLarge is returned.
+func makeReplayable(req *http.Request, limit int64) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return req, nil
+	}
+
+	r := req.Clone(req.Context())
+	head, err := io.ReadAll(io.LimitReader(req.Body, limit+1))
+	if err != nil {
+		req.Body.Close()
+		return nil, err
+	}
+
+	if int64(len(head)) > limit {
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(head), req.Body), req.Body}
+		return r, errBodyTooLarge
+	}
+
+	req.Body.Close()
+	r.ContentLength = int64(len(head))
+	r.Body = io.NopCloser(bytes.NewReader(head))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return &bufferedBody{Reader: bytes.NewReader(head)}, nil
+	}
+
+	return r, nil
+}
+
+// errBodyNotReusable is returned when a teed request body cannot be
+// replayed because it was not sent completely or exceeded the limit.
+var errBodyNotReusable = errors.New("request body cannot be replayed")
+
+// teeBody passes a request body through to the transport while copying it
+// into memory, so it can be replayed after the first attempt.
+type teeBody struct {
+	src   io.ReadCloser
+	limit int64 // Maximum number of bytes copied into buf
+	size  int64 // Declared length of the body, 0 if unknown
+
+	mu       sync.Mutex // Guards the fields below, the transport may read concurrently
+	buf      bytes.Buffer
+	done     bool // src was read up to io.EOF
+	overflow bool // src exceeded limit, buf was discarded
+}
+
+func (t *teeBody) Read(p []byte) (int, error) {
+	n, err := t.src.Read(p)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.overflow {
+		if int64(t.buf.Len()+n) > t.limit {
+			t.overflow = true
+			t.buf = bytes.Buffer{}
+		} else {
+			t.buf.Write(p[:n])
+		}
+	}
+	if err == io.EOF {
+		t.done = true
+	}
+
+	return n, err
+}
+
+func (t *teeBody) Close() error {
+	return t.src.Close()
+}
+
+// replay returns the copied body, or false if it is incomplete.
+func (t *teeBody) replay() ([]byte, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	complete := t.done || (t.size > 0 && int64(t.buf.Len()) == t.size)
+	return t.buf.Bytes(), complete && !t.overflow
+}
+
+// makeReusable prepares req for being sent multiple times by copying its
+// body while the first attempt sends it. Requests that already have GetBody,
+// or no body at all, are returned unchanged with a nil teeBody. Otherwise
+// GetBody of the returned request yields the original body once and the
+// copy afterwards.
+func makeReusable(req *http.Request, limit int64) (*http.Request, *teeBody) {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return req, nil
+	}
+
+	tee := &teeBody{src: req.Body, limit: limit, size: max(req.ContentLength, 0)}
+	var sent atomic.Bool
+
+	r := req.Clone(req.Context())
+	r.GetBody = func() (io.ReadCloser, error) {
+		if !sent.Swap(true) {
+			return tee, nil
+		}
+
+		data, ok := tee.replay()
+		if !ok {
+			return nil, errBodyNotReusable
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+
+	return r, tee
+}
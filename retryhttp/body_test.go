@@ -0,0 +1,146 @@
+package retryhttp
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+)
+
+// onlyReader hides every method of the wrapped reader except Read, so the
+// body cannot be rewound by http.NewRequest.
+type onlyReader struct{ io.Reader }
+
+// TestNewBufferedRetryBody verifies that the buffered body can be read,
+// rewound and read again.
+func TestNewBufferedRetryBody(t *testing.T) {
+	body := NewBufferedRetryBody(onlyReader{strings.NewReader("payload")})
+
+	first, _ := io.ReadAll(body)
+	if string(first) != "payload" {
+		t.Fatalf("expected 'payload', got %q", first)
+	}
+
+	seeker, ok := body.(io.Seeker)
+	if !ok {
+		t.Fatal("expected buffered body to implement io.Seeker")
+	}
+
+	if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+		t.Fatalf("expected no error rewinding, got %v", err)
+	}
+
+	second, _ := io.ReadAll(body)
+	if string(second) != "payload" {
+		t.Errorf("expected 'payload' after rewind, got %q", second)
+	}
+}
+
+// TestNewBufferedRetryBodyReadError verifies that an error encountered while
+// buffering is returned by Read.
+func TestNewBufferedRetryBodyReadError(t *testing.T) {
+	readErr := errors.New("socket closed")
+	body := NewBufferedRetryBody(iotest.ErrReader(readErr))
+
+	if _, err := io.ReadAll(body); !errors.Is(err, readErr) {
+		t.Errorf("expected read error, got %v", err)
+	}
+}
+
+// TestClientDoBuffersNonSeekableBody verifies that bodies without GetBody
+// are buffered and replayed, and that bodies above the limit are sent once.
+func TestClientDoBuffersNonSeekableBody(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		opts          []Option
+		expectedCalls int
+	}{
+		{name: "within limit", opts: nil, expectedCalls: 3},
+		{name: "above limit", opts: []Option{WithMaxBodyBuffer(3)}, expectedCalls: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var mu sync.Mutex
+			var bodies []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := io.ReadAll(r.Body)
+				mu.Lock()
+				bodies = append(bodies, string(data))
+				mu.Unlock()
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer server.Close()
+
+			req, _ := http.NewRequest(http.MethodPost, server.URL, onlyReader{strings.NewReader("payload")})
+			_, err := NewClient(newTestRetry(3), tc.opts...).Do(req)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+
+			if len(bodies) != tc.expectedCalls {
+				t.Fatalf("expected %d calls, got %d", tc.expectedCalls, len(bodies))
+			}
+
+			for i, body := range bodies {
+				if body != "payload" {
+					t.Errorf("attempt %d: expected body 'payload', got %q", i+1, body)
+				}
+			}
+		})
+	}
+}
+
+// TestWithHTTPBodyReuse verifies that a streamed body is replayed
+// identically on every attempt and that larger bodies are sent only once.
+func TestWithHTTPBodyReuse(t *testing.T) {
+	t.Parallel()
+	payload := strings.Repeat("payload ", 1000)
+
+	testCases := []struct {
+		name          string
+		limit         int64
+		expectedCalls int
+	}{
+		{name: "within limit", limit: int64(len(payload)), expectedCalls: 3},
+		{name: "above limit", limit: int64(len(payload)) - 1, expectedCalls: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var mu sync.Mutex
+			var bodies []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := io.ReadAll(r.Body)
+				mu.Lock()
+				bodies = append(bodies, string(data))
+				mu.Unlock()
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer server.Close()
+
+			req, _ := http.NewRequest(http.MethodPost, server.URL, onlyReader{strings.NewReader(payload)})
+			_, err := NewClient(newTestRetry(3), WithHTTPBodyReuse(tc.limit)).Do(req)
+			if err == nil || !strings.Contains(err.Error(), "503") {
+				t.Fatalf("expected the status error of the last attempt, got %v", err)
+			}
+
+			if len(bodies) != tc.expectedCalls {
+				t.Fatalf("expected %d calls, got %d", tc.expectedCalls, len(bodies))
+			}
+			for i, body := range bodies {
+				if body != payload {
+					t.Errorf("attempt %d: expected the full payload, got %d bytes", i+1, len(body))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,363 @@
+// Package retryhttp provides HTTP helpers built on top of the retry package.
+// It retries requests that fail with transport errors or retryable status
+// codes, rewinds request bodies between attempts and respects the
+// Retry-After header sent by servers.
+package retryhttp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	retry "github.com/1amDudman/try-again-go"
+)
+
+// StatusError is returned for responses whose status code is considered
+// retryable. When all attempts fail, it is reachable from the returned
+// error with errors.As.
+type StatusError struct {
+	StatusCode int           // HTTP status code of the response
+	RetryAfter time.Duration // Delay requested by the Retry-After header, if any
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("retryable HTTP status %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Client executes HTTP requests with retries. Use NewClient() to create
+// instances with sensible defaults and functional options for customization.
+type Client struct {
+	rc            *retry.RetryConfig // Retry policy applied to every request
+	client        *http.Client       // Client used to send requests
+	retryable     map[int]bool       // Status codes that trigger a retry
+	maxBodyBuffer int64              // Limit for buffering non-rewindable bodies
+
+	clientOverride func(attempt int) *http.Client // Selects the client per attempt
+	hosts          []string                       // Hosts rotated through on each attempt
+
+	responseTimeout time.Duration // Limit for reading the response body, 0 means unbounded
+
+	cloner func(*http.Request) *http.Request // Produces a fresh request per attempt
+
+	requestTimeout time.Duration // Timeout of a single attempt, 0 keeps the client's timeout
+
+	validator func(*http.Response) error // Inspects successful responses, e.g. their body
+
+	proxy func(attempt int) *url.URL // Selects the proxy per attempt, nil means ProxyFromEnvironment
+
+	tlsConfig     func(attempt int) *tls.Config   // Selects the TLS configuration per attempt
+	tlsMu         sync.Mutex                      // Guards tlsTransports
+	tlsTransports map[*tls.Config]*http.Transport // Transports created for the TLS configurations
+
+	idempotencyHeader string        // Header carrying the idempotency token, empty means disabled
+	idempotencyToken  func() string // Generates a token per Do call
+
+	bodyReuse int64 // Limit for teeing non-rewindable bodies, 0 means buffering up front
+}
+
+// attemptKey is the request context key under which attempt stores the
+// attempt number for the proxy selector.
+type attemptKey struct{}
+
+// NewClient creates a new Client that applies the given retry policy. By
+// default requests are sent with a client using http.DefaultTransport and
+// the following status codes are retried:
+//   - 429 Too Many Requests
+//   - 500 Internal Server Error
+//   - 502 Bad Gateway
+//   - 503 Service Unavailable
+//   - 504 Gateway Timeout
+//
+// Example:
+//
+//	client := retryhttp.NewClient(
+//	    retry.NewRetry(retry.WithAttempts(5)),
+//	    retryhttp.WithCloudflareRetryable(),
+//	)
+//	resp, err := client.Do(req)
+func NewClient(rc *retry.RetryConfig, opts ...Option) *Client {
+	c := &Client{
+		rc:            rc,
+		client:        &http.Client{Transport: http.DefaultTransport},
+		maxBodyBuffer: defaultMaxBodyBuffer,
+		retryable: map[int]bool{
+			http.StatusTooManyRequests:     true,
+			http.StatusInternalServerError: true,
+			http.StatusBadGateway:          true,
+			http.StatusServiceUnavailable:  true,
+			http.StatusGatewayTimeout:      true,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.proxy != nil {
+		c.client = c.withProxy(c.client)
+	}
+
+	return c
+}
+
+// Do sends the request, retrying on transport errors and retryable status
+// codes according to the client's retry policy. The request context is used
+// for the whole retry loop. Request bodies are rewound between attempts
+// using req.GetBody, which http.NewRequest sets for common body types.
+// Bodies without GetBody are buffered in memory; if such a body exceeds the
+// limit set with WithMaxBodyBuffer, the request is sent only once.
+// WithHTTPBodyReuse copies such bodies while they are sent instead, and a
+// cloner set with WithHTTPRequestCloner replaces this handling.
+//
+// On success the response is returned and the caller must close its body.
+// Bodies of responses that are retried are drained and closed.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	req = c.withIdempotencyToken(req)
+
+	var tee *teeBody
+	switch {
+	case c.cloner != nil:
+	case c.bodyReuse > 0:
+		req, tee = makeReusable(req, c.bodyReuse)
+	default:
+		var err error
+		req, err = makeReplayable(req, c.maxBodyBuffer)
+		if errors.Is(err, errBodyTooLarge) {
+			return c.attempt(req.Context(), req, 1)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("buffer request body: %w", err)
+		}
+	}
+
+	// Attempts abandoned by WithMaxAttemptDuration may still finish
+	// while the next one runs, hence the lock.
+	var mu sync.Mutex
+	var sent bool
+	var lastErr error
+	return retry.DoWithAttempt(req.Context(), c.rc, func(attempt int) (*http.Response, error) {
+		mu.Lock()
+		resend, prevErr := sent, lastErr
+		sent = true
+		mu.Unlock()
+
+		if tee != nil && resend {
+			if _, ok := tee.replay(); !ok {
+				return nil, retry.NonRetryable(fmt.Errorf("%w: %v", errBodyNotReusable, prevErr))
+			}
+		}
+
+		resp, err := c.attempt(req.Context(), req, attempt)
+		mu.Lock()
+		lastErr = err
+		mu.Unlock()
+		return resp, err
+	})
+}
+
+// httpClient returns the client to use for the given attempt, with the
+// per-attempt timeout applied.
+func (c *Client) httpClient(attempt int) *http.Client {
+	client := c.client
+	if c.tlsConfig != nil {
+		client = c.withTLSConfig(attempt)
+	}
+	if c.clientOverride != nil {
+		if override := c.clientOverride(attempt); override != nil {
+			client = override
+		}
+	}
+
+	if c.requestTimeout > 0 {
+		withTimeout := *client
+		withTimeout.Timeout = c.requestTimeout
+		return &withTimeout
+	}
+
+	return client
+}
+
+// withTLSConfig returns a copy of the client whose transport uses the TLS
+// configuration selected for the attempt. A transport is created once per
+// configuration and reused by later attempts, so they share its connection
+// pool. If the configuration is nil or the client's transport is not an
+// *http.Transport, the client is returned unchanged.
+func (c *Client) withTLSConfig(attempt int) *http.Client {
+	cfg := c.tlsConfig(attempt)
+	if cfg == nil {
+		return c.client
+	}
+
+	rt := c.client.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	base, ok := rt.(*http.Transport)
+	if !ok {
+		return c.client
+	}
+
+	c.tlsMu.Lock()
+	t, ok := c.tlsTransports[cfg]
+	if !ok {
+		t = base.Clone()
+		t.TLSClientConfig = cfg
+		if c.tlsTransports == nil {
+			c.tlsTransports = map[*tls.Config]*http.Transport{}
+		}
+		c.tlsTransports[cfg] = t
+	}
+	c.tlsMu.Unlock()
+
+	withTLS := *c.client
+	withTLS.Transport = t
+	return &withTLS
+}
+
+// withProxy returns a copy of client whose transport selects the proxy with
+// the proxy function. Clients with transports other than *http.Transport
+// are returned unchanged.
+func (c *Client) withProxy(client *http.Client) *http.Client {
+	rt := client.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	t, ok := rt.(*http.Transport)
+	if !ok {
+		return client
+	}
+
+	t = t.Clone()
+	t.Proxy = func(req *http.Request) (*url.URL, error) {
+		if attempt, ok := req.Context().Value(attemptKey{}).(int); ok {
+			if proxyURL := c.proxy(attempt); proxyURL != nil {
+				return proxyURL, nil
+			}
+		}
+		return http.ProxyFromEnvironment(req)
+	}
+
+	withProxy := *client
+	withProxy.Transport = t
+	return &withProxy
+}
+
+// attempt sends a fresh copy of req once and converts retryable status
+// codes into errors.
+func (c *Client) attempt(ctx context.Context, req *http.Request, attempt int) (*http.Response, error) {
+	r, err := c.cloneRequest(ctx, req)
+	if err != nil {
+		return nil, retry.NonRetryable(err)
+	}
+
+	if c.proxy != nil {
+		r = r.WithContext(context.WithValue(r.Context(), attemptKey{}, attempt))
+	}
+
+	if len(c.hosts) > 0 {
+		host := c.hosts[(attempt-1)%len(c.hosts)]
+		r.URL.Host = host
+		r.Host = host
+	}
+
+	resp, err := c.httpClient(attempt).Do(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.retryable[resp.StatusCode] {
+		if c.responseTimeout > 0 {
+			resp.Body = NewDeadlineReader(resp.Body, c.responseTimeout)
+		}
+		if c.validator != nil {
+			return c.validate(resp)
+		}
+		return resp, nil
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	statusErr := &StatusError{StatusCode: resp.StatusCode}
+	if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		statusErr.RetryAfter = d
+		return nil, retry.RetryAfter(statusErr, d)
+	}
+
+	return nil, statusErr
+}
+
+// validate buffers the body of resp and passes the response to the
+// validator. The returned response has a fresh reader over the buffer, so
+// the caller sees the body unread.
+func (c *Client) validate(resp *http.Response) (*http.Response, error) {
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	if err := c.validator(resp); err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	return resp, nil
+}
+
+// cloneRequest returns a fresh copy of req for a single attempt, using the
+// cloner set with WithHTTPRequestCloner if any.
+func (c *Client) cloneRequest(ctx context.Context, req *http.Request) (*http.Request, error) {
+	if c.cloner != nil {
+		r := c.cloner(req)
+		if r == nil {
+			return nil, errors.New("request cloner returned nil")
+		}
+		return r, nil
+	}
+
+	r := req.Clone(ctx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("rewind request body: %w", err)
+		}
+		r.Body = body
+	}
+
+	return r, nil
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either
+// a number of seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		d := date.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,162 @@
+package retryhttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	retry "github.com/1amDudman/try-again-go"
+)
+
+// newTestRetry creates a fast retry policy for HTTP tests.
+func newTestRetry(attempts int) *retry.RetryConfig {
+	return retry.NewRetry(retry.WithAttempts(attempts), retry.WithDelay(time.Millisecond))
+}
+
+// TestClientDoRetriesStatus verifies that retryable status codes are retried
+// and that the request body is sent again on every attempt.
+func TestClientDoRetriesStatus(t *testing.T) {
+	t.Parallel()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := make([]byte, 7)
+		n, _ := r.Body.Read(body)
+		if string(body[:n]) != "payload" {
+			t.Errorf("expected body 'payload', got %q", body[:n])
+		}
+
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+	resp, err := NewClient(newTestRetry(3)).Do(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 calls, got %d", calls.Load())
+	}
+}
+
+// TestClientDoCloudflareRetryable verifies that Cloudflare status codes are
+// only retried when WithCloudflareRetryable is set and that the Retry-After
+// header is respected.
+func TestClientDoCloudflareRetryable(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		opts          []Option
+		expectedCalls int32
+		expectedCode  int
+	}{
+		{name: "default", opts: nil, expectedCalls: 1, expectedCode: 522},
+		{name: "cloudflare", opts: []Option{WithCloudflareRetryable()}, expectedCalls: 2, expectedCode: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var calls atomic.Int32
+			var first time.Time
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) == 1 {
+					first = time.Now()
+					w.Header().Set("Retry-After", "1")
+					w.WriteHeader(522)
+					return
+				}
+				if time.Since(first) < time.Second {
+					t.Errorf("expected Retry-After delay to be respected, retried after %v", time.Since(first))
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			resp, err := NewClient(newTestRetry(2), tc.opts...).Do(req)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedCode {
+				t.Errorf("expected status %d, got %d", tc.expectedCode, resp.StatusCode)
+			}
+
+			if calls.Load() != tc.expectedCalls {
+				t.Errorf("expected %d calls, got %d", tc.expectedCalls, calls.Load())
+			}
+		})
+	}
+}
+
+// TestClientDoExhausted verifies that a StatusError is returned when all
+// attempts receive a retryable status code.
+func TestClientDoExhausted(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := NewClient(newTestRetry(2)).Do(req)
+	if resp != nil {
+		t.Errorf("expected no response, got %v", resp.Status)
+	}
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected StatusError, got %v", err)
+	}
+
+	if statusErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("expected status 502, got %d", statusErr.StatusCode)
+	}
+}
+
+// TestParseRetryAfter verifies parsing of both Retry-After header formats.
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"empty", "", 0, false},
+		{"seconds", "30", 30 * time.Second, true},
+		{"negative", "-1", 0, false},
+		{"http date", now.Add(time.Minute).Format(http.TimeFormat), time.Minute, true},
+		{"past date", now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"invalid", "soon", 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			d, ok := parseRetryAfter(tc.value, now)
+			if d != tc.expected || ok != tc.ok {
+				t.Errorf("expected (%v, %v), got (%v, %v)", tc.expected, tc.ok, d, ok)
+			}
+		})
+	}
+}
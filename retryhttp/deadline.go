@@ -0,0 +1,53 @@
+package retryhttp
+
+import (
+	"errors"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// ErrResponseTimeout is returned when reading a response body takes longer
+// than the timeout set with WithResponseTimeout. It is not marked as
+// non-retryable, so reading the body inside a retry function retries the
+// request.
+var ErrResponseTimeout = errors.New("response read timeout")
+
+// DeadlineReader wraps a response body and aborts reading once a deadline
+// has passed. Reads that are blocked when the deadline passes are
+// interrupted by closing the underlying body. Use NewDeadlineReader() to
+// create instances.
+type DeadlineReader struct {
+	body    io.ReadCloser
+	timer   *time.Timer
+	expired atomic.Bool
+}
+
+// NewDeadlineReader returns a DeadlineReader that fails reads with
+// ErrResponseTimeout once d has elapsed. Closing the reader stops the timer.
+func NewDeadlineReader(body io.ReadCloser, d time.Duration) *DeadlineReader {
+	r := &DeadlineReader{body: body}
+	r.timer = time.AfterFunc(d, func() {
+		r.expired.Store(true)
+		body.Close()
+	})
+
+	return r
+}
+
+// Read reads from the underlying body. Once the deadline has passed it
+// returns ErrResponseTimeout instead of the error of the closed body.
+func (r *DeadlineReader) Read(p []byte) (int, error) {
+	n, err := r.body.Read(p)
+	if err != nil && err != io.EOF && r.expired.Load() {
+		return n, ErrResponseTimeout
+	}
+
+	return n, err
+}
+
+// Close stops the deadline timer and closes the underlying body.
+func (r *DeadlineReader) Close() error {
+	r.timer.Stop()
+	return r.body.Close()
+}
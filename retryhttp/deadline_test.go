@@ -0,0 +1,72 @@
+package retryhttp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	retry "github.com/1amDudman/try-again-go"
+)
+
+// TestWithResponseTimeout verifies that a slow body fails with the
+// retryable ErrResponseTimeout while fast bodies are read completely.
+func TestWithResponseTimeout(t *testing.T) {
+	t.Parallel()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		if requests.Add(1) == 1 {
+			// The first response stalls before sending its body.
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		_, _ = w.Write([]byte("done"))
+	}))
+	defer server.Close()
+
+	client := NewClient(newTestRetry(1), WithResponseTimeout(50*time.Millisecond))
+	body, err := retry.Do(context.Background(), newTestRetry(3), func() ([]byte, error) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil && !errors.Is(err, ErrResponseTimeout) {
+			t.Errorf("expected ErrResponseTimeout, got %v", err)
+		}
+		return body, err
+	})
+
+	if err != nil || string(body) != "done" {
+		t.Fatalf("expected body %q after a retry, got %q, %v", "done", body, err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected 2 requests, got %d", got)
+	}
+}
+
+// TestDeadlineReaderClose verifies that closing the reader before the
+// deadline stops the timer and closes the body.
+func TestDeadlineReaderClose(t *testing.T) {
+	t.Parallel()
+	r := NewDeadlineReader(io.NopCloser(strings.NewReader("payload")), time.Hour)
+	if err := r.Close(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if r.timer.Stop() {
+		t.Error("expected the timer to be stopped by Close")
+	}
+}
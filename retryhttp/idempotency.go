@@ -0,0 +1,15 @@
+package retryhttp
+
+import "net/http"
+
+// withIdempotencyToken returns a copy of req carrying a new idempotency
+// token, or req itself if no token is configured or it already has one.
+func (c *Client) withIdempotencyToken(req *http.Request) *http.Request {
+	if c.idempotencyHeader == "" || req.Header.Get(c.idempotencyHeader) != "" {
+		return req
+	}
+
+	r := req.Clone(req.Context())
+	r.Header.Set(c.idempotencyHeader, c.idempotencyToken())
+	return r
+}
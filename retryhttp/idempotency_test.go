@@ -0,0 +1,93 @@
+package retryhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// uuidV4 matches the tokens generated by default.
+var uuidV4 = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+// TestWithHTTPIdempotencyToken verifies that all attempts of a Do call send
+// the same token and that every Do call gets a new one.
+func TestWithHTTPIdempotencyToken(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		tokens = append(tokens, r.Header.Get("Idempotency-Key"))
+		n := len(tokens)
+		mu.Unlock()
+		if n%3 != 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewClient(newTestRetry(3), WithHTTPIdempotencyToken("Idempotency-Key", nil))
+	for range 2 {
+		req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"amount": 10}`))
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		resp.Body.Close()
+
+		if req.Header.Get("Idempotency-Key") != "" {
+			t.Error("expected the original request to be left unchanged")
+		}
+	}
+
+	if len(tokens) != 6 {
+		t.Fatalf("expected 6 requests, got %d", len(tokens))
+	}
+	for i, token := range tokens {
+		if !uuidV4.MatchString(token) {
+			t.Errorf("request %d: expected a UUID v4 token, got %q", i+1, token)
+		}
+		if token != tokens[i/3*3] {
+			t.Errorf("request %d: expected the token of its Do call %q, got %q", i+1, tokens[i/3*3], token)
+		}
+	}
+	if tokens[0] == tokens[3] {
+		t.Errorf("expected a new token per Do call, got %q twice", tokens[0])
+	}
+}
+
+// TestWithHTTPIdempotencyTokenCustom verifies that a custom generator is
+// used and that a token set by the caller is kept.
+func TestWithHTTPIdempotencyTokenCustom(t *testing.T) {
+	t.Parallel()
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("X-Request-Token"))
+	}))
+	defer server.Close()
+
+	client := NewClient(newTestRetry(3), WithHTTPIdempotencyToken("X-Request-Token", func() string { return "generated" }))
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	req.Header.Set("X-Request-Token", "caller")
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if len(got) != 2 || got[0] != "generated" || got[1] != "caller" {
+		t.Errorf("expected tokens [generated caller], got %v", got)
+	}
+}
@@ -0,0 +1,266 @@
+package retryhttp
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/1amDudman/try-again-go/internal/uuid"
+)
+
+// Option defines a function type for configuring Client using the
+// functional options pattern.
+type Option func(*Client)
+
+// WithRetryableStatus adds status codes that should trigger a retry in
+// addition to the defaults.
+//
+// Example:
+//
+//	retryhttp.NewClient(rc, retryhttp.WithRetryableStatus(http.StatusRequestTimeout))
+func WithRetryableStatus(codes ...int) Option {
+	return func(c *Client) {
+		for _, code := range codes {
+			c.retryable[code] = true
+		}
+	}
+}
+
+// WithCloudflareRetryable treats the Cloudflare specific status codes
+// 520–527 as retryable. These are returned by Cloudflare when the origin
+// server is unreachable, times out or fails the TLS handshake, and usually
+// resolve on their own. A Retry-After header sent with such a response is
+// respected like for any other retryable status.
+//
+// Example:
+//
+//	retryhttp.NewClient(rc, retryhttp.WithCloudflareRetryable())
+func WithCloudflareRetryable() Option {
+	return WithRetryableStatus(520, 521, 522, 523, 524, 525, 526, 527)
+}
+
+// WithMaxBodyBuffer sets the maximum size in bytes of a request body that is
+// buffered in memory when it cannot be rewound through req.GetBody. Requests
+// with larger bodies are sent once without retries. The default is 10 MiB.
+//
+// Example:
+//
+//	retryhttp.NewClient(rc, retryhttp.WithMaxBodyBuffer(1<<20))
+func WithMaxBodyBuffer(n int64) Option {
+	return func(c *Client) {
+		c.maxBodyBuffer = n
+	}
+}
+
+// WithHTTPTransport sets the http.RoundTripper used to send requests
+// instead of http.DefaultTransport, e.g. a transport with a custom
+// connection pool, TLS configuration or proxy. Clients selected with
+// WithHTTPClientOverride keep their own transport.
+//
+// Example:
+//
+//	retryhttp.NewClient(rc, retryhttp.WithHTTPTransport(&http.Transport{
+//	    MaxIdleConnsPerHost: 32,
+//	    TLSClientConfig:     tlsConfig,
+//	}))
+func WithHTTPTransport(t http.RoundTripper) Option {
+	return func(c *Client) {
+		c.client = &http.Client{Transport: t}
+	}
+}
+
+// WithHTTPRequestTimeout limits every attempt to d by sending it with a
+// copy of the client whose Timeout is set to d. The timeout covers
+// connecting, sending the request and reading the response body, and
+// replaces the Timeout of the configured client. An attempt that times out
+// is retried. The retry loop as a whole is bounded by the request context.
+//
+// Example:
+//
+//	retryhttp.NewClient(rc, retryhttp.WithHTTPRequestTimeout(2*time.Second))
+func WithHTTPRequestTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.requestTimeout = d
+	}
+}
+
+// WithHTTPClientOverride sets a function that selects the http.Client used
+// for each attempt, starting at 1. This allows switching clients between
+// retries, e.g. from a primary client with keep-alive to a fallback client
+// without it. Returning nil uses the default client.
+//
+// Example:
+//
+//	retryhttp.NewClient(rc, retryhttp.WithHTTPClientOverride(func(attempt int) *http.Client {
+//	    if attempt > 1 {
+//	        return fallbackClient
+//	    }
+//	    return primaryClient
+//	}))
+func WithHTTPClientOverride(fn func(attempt int) *http.Client) Option {
+	return func(c *Client) {
+		c.clientOverride = fn
+	}
+}
+
+// WithHostRotation rotates the target host on every attempt, starting with
+// hosts[0] for the first attempt and wrapping around. The URL host and the
+// Host header of the request are replaced, so retries reach a different
+// server or IP when the previous one is down. Hosts may include a port.
+//
+// Example:
+//
+//	retryhttp.NewClient(rc, retryhttp.WithHostRotation([]string{
+//	    "api-1.example.com",
+//	    "api-2.example.com",
+//	}))
+func WithHostRotation(hosts []string) Option {
+	return func(c *Client) {
+		c.hosts = hosts
+	}
+}
+
+// WithResponseTimeout limits the time available for reading the body of a
+// successful response, measured from the moment Do returns it. Unlike
+// http.Client.Timeout, which also covers connecting and waiting for the
+// headers, it only bounds the body transfer. Once exceeded, reads fail with
+// ErrResponseTimeout, which is retryable when the body is read inside a
+// retry function.
+//
+// Example:
+//
+//	retryhttp.NewClient(rc, retryhttp.WithResponseTimeout(30*time.Second))
+func WithResponseTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.responseTimeout = d
+	}
+}
+
+// WithHTTPRequestCloner sets a function that produces a fresh request for
+// every attempt, including the first. The original request is passed each
+// time and must not be modified. By default the request is cloned with its
+// headers and the body is rewound with req.GetBody, buffering bodies that
+// cannot be rewound. A custom cloner replaces this handling completely and
+// can regenerate bodies that are neither rewindable nor small enough to be
+// buffered, e.g. by reopening a file.
+//
+// Example:
+//
+//	retryhttp.NewClient(rc, retryhttp.WithHTTPRequestCloner(func(req *http.Request) *http.Request {
+//	    r := req.Clone(req.Context())
+//	    r.Body, _ = os.Open(uploadPath)
+//	    return r
+//	}))
+func WithHTTPRequestCloner(fn func(*http.Request) *http.Request) Option {
+	return func(c *Client) {
+		c.cloner = fn
+	}
+}
+
+// WithHTTPResponseValidator sets a function that inspects every response
+// whose status code is not retryable, e.g. to detect errors reported in the
+// body of a 200 OK by RPC-over-HTTP or legacy APIs. If fn returns an error
+// the response is discarded and the error is handled like a failed attempt:
+// it is retried unless it is marked with retry.NonRetryable.
+//
+// The body is buffered in memory before fn is called, so fn may read it and
+// the caller still receives the complete body.
+//
+// Example:
+//
+//	retryhttp.NewClient(rc, retryhttp.WithHTTPResponseValidator(func(resp *http.Response) error {
+//	    var reply struct{ Error string }
+//	    if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+//	        return retry.NonRetryable(err)
+//	    }
+//	    if reply.Error != "" {
+//	        return errors.New(reply.Error)
+//	    }
+//	    return nil
+//	}))
+func WithHTTPResponseValidator(fn func(*http.Response) error) Option {
+	return func(c *Client) {
+		c.validator = fn
+	}
+}
+
+// WithHTTPProxy sets a function that selects the proxy for each attempt,
+// starting at 1, e.g. to rotate through proxies and spread rate limits.
+// Returning nil falls back to http.ProxyFromEnvironment, which is also the
+// behavior without this option. The proxy is set on a copy of the client's
+// transport, which must be an *http.Transport; clients selected with
+// WithHTTPClientOverride keep their own proxy settings.
+//
+// Example:
+//
+//	retryhttp.NewClient(rc, retryhttp.WithHTTPProxy(func(attempt int) *url.URL {
+//	    return proxies[(attempt-1)%len(proxies)]
+//	}))
+func WithHTTPProxy(fn func(attempt int) *url.URL) Option {
+	return func(c *Client) {
+		c.proxy = fn
+	}
+}
+
+// WithHTTPTLSConfig sets a function that selects the TLS configuration for
+// each attempt, starting at 1, e.g. to rotate client certificates in mutual
+// TLS environments. Each distinct configuration gets its own copy of the
+// client's transport, which must be an *http.Transport; return the same
+// *tls.Config value for attempts that may share connections. Returning nil
+// keeps the transport's own TLS configuration. Clients selected with
+// WithHTTPClientOverride keep their own transport.
+//
+// Example:
+//
+//	configs := []*tls.Config{
+//	    {Certificates: []tls.Certificate{currentCert}},
+//	    {Certificates: []tls.Certificate{previousCert}},
+//	}
+//	retryhttp.NewClient(rc, retryhttp.WithHTTPTLSConfig(func(attempt int) *tls.Config {
+//	    return configs[(attempt-1)%len(configs)]
+//	}))
+func WithHTTPTLSConfig(fn func(attempt int) *tls.Config) Option {
+	return func(c *Client) {
+		c.tlsConfig = fn
+	}
+}
+
+// WithHTTPIdempotencyToken makes POST and other non-idempotent requests
+// safe to retry with servers that deduplicate requests by token, such as
+// payment APIs. genFn is called once per Client.Do call, and the token is
+// sent under headerName with every attempt of that call. A nil genFn
+// generates random UUID v4 tokens. Requests that already carry the header
+// keep their token.
+//
+// Example:
+//
+//	retryhttp.NewClient(rc, retryhttp.WithHTTPIdempotencyToken("Idempotency-Key", nil))
+func WithHTTPIdempotencyToken(headerName string, genFn func() string) Option {
+	if genFn == nil {
+		genFn = uuid.New
+	}
+
+	return func(c *Client) {
+		c.idempotencyHeader = headerName
+		c.idempotencyToken = genFn
+	}
+}
+
+// WithHTTPBodyReuse makes request bodies that cannot be rewound through
+// req.GetBody replayable by copying them into memory while the first
+// attempt streams them, instead of buffering them completely before the
+// first attempt as WithMaxBodyBuffer does. Retries replay the copy. If the
+// body is larger than maxBodySize bytes, or the first attempt did not send
+// it completely, the request is not retried and the error of the first
+// attempt is returned wrapped in a non-retryable error. Bodies with GetBody
+// are rewound as usual.
+//
+// Example:
+//
+//	retryhttp.NewClient(rc, retryhttp.WithHTTPBodyReuse(32<<20))
+func WithHTTPBodyReuse(maxBodySize int64) Option {
+	return func(c *Client) {
+		c.bodyReuse = maxBodySize
+	}
+}
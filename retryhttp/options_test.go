@@ -0,0 +1,427 @@
+package retryhttp
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	retry "github.com/1amDudman/try-again-go"
+)
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// TestWithHTTPClientOverride verifies that the client is selected per
+// attempt.
+func TestWithHTTPClientOverride(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	failing := &http.Client{Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})}
+
+	var used []int
+	client := NewClient(newTestRetry(3), WithHTTPClientOverride(func(attempt int) *http.Client {
+		used = append(used, attempt)
+		if attempt == 1 {
+			return failing
+		}
+		return server.Client()
+	}))
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	if len(used) != 2 || used[0] != 1 || used[1] != 2 {
+		t.Errorf("expected client override for attempts [1 2], got %v", used)
+	}
+}
+
+// TestWithHostRotation verifies that each attempt targets the next host of
+// the list and sets the Host header accordingly.
+func TestWithHostRotation(t *testing.T) {
+	t.Parallel()
+	var urlHosts, headerHosts []string
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		urlHosts = append(urlHosts, req.URL.Host)
+		headerHosts = append(headerHosts, req.Host)
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Body:       http.NoBody,
+			Header:     http.Header{},
+		}, nil
+	})
+
+	hosts := []string{"a.example.com", "b.example.com:8080"}
+	client := NewClient(newTestRetry(3),
+		WithHostRotation(hosts),
+		WithHTTPClientOverride(func(int) *http.Client { return &http.Client{Transport: transport} }),
+	)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://origin.example.com/path", nil)
+	if _, err := client.Do(req); err == nil {
+		t.Fatal("expected an error after exhausting all attempts")
+	}
+
+	want := []string{"a.example.com", "b.example.com:8080", "a.example.com"}
+	if !slices.Equal(urlHosts, want) {
+		t.Errorf("expected URL hosts %v, got %v", want, urlHosts)
+	}
+	if !slices.Equal(headerHosts, want) {
+		t.Errorf("expected Host headers %v, got %v", want, headerHosts)
+	}
+	if req.URL.Host != "origin.example.com" {
+		t.Errorf("expected the original request to be unchanged, got host %q", req.URL.Host)
+	}
+}
+
+// TestWithHostRotationAttemptFilter verifies that hosts are selected by the
+// attempt number of the retry loop, which skips attempts rejected by an
+// attempt filter.
+func TestWithHostRotationAttemptFilter(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	var urlHosts []string
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		urlHosts = append(urlHosts, req.URL.Host)
+		mu.Unlock()
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody, Header: http.Header{}}, nil
+	})
+
+	rc := retry.NewRetry(
+		retry.WithAttempts(3),
+		retry.WithDelay(time.Millisecond),
+		retry.WithMaxAttemptDuration(time.Second),
+		retry.WithAttemptFilter(func(attempt int, _ error) bool { return attempt != 2 }),
+	)
+	client := NewClient(rc,
+		WithHostRotation([]string{"a.example.com", "b.example.com", "c.example.com"}),
+		WithHTTPClientOverride(func(int) *http.Client { return &http.Client{Transport: transport} }),
+	)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://origin.example.com/path", nil)
+	if _, err := client.Do(req); err == nil {
+		t.Fatal("expected an error after exhausting all attempts")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []string{"a.example.com", "c.example.com"}; !slices.Equal(urlHosts, want) {
+		t.Errorf("expected URL hosts %v, got %v", want, urlHosts)
+	}
+}
+
+// TestWithHTTPTransport verifies that requests are sent through the
+// configured transport.
+func TestWithHTTPTransport(t *testing.T) {
+	t.Parallel()
+	var calls int
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		status := http.StatusOK
+		if calls == 1 {
+			status = http.StatusBadGateway
+		}
+		return &http.Response{StatusCode: status, Body: http.NoBody, Header: http.Header{}, Request: req}, nil
+	})
+
+	client := NewClient(newTestRetry(3), WithHTTPTransport(transport))
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if calls != 2 {
+		t.Errorf("expected 2 round trips through the transport, got %d", calls)
+	}
+}
+
+// TestWithHTTPRequestCloner verifies that POST bodies are sent completely on
+// every attempt, with the default and with a custom cloner.
+func TestWithHTTPRequestCloner(t *testing.T) {
+	t.Parallel()
+	payload := []byte(`{"order":42}`)
+
+	testCases := []struct {
+		name       string
+		opts       func(calls *int) []Option
+		expectCall int
+	}{
+		{
+			name:       "default cloner rewinds bytes.Reader",
+			opts:       func(*int) []Option { return nil },
+			expectCall: 0,
+		},
+		{
+			name: "custom cloner regenerates the body",
+			opts: func(calls *int) []Option {
+				return []Option{WithHTTPRequestCloner(func(req *http.Request) *http.Request {
+					*calls++
+					r := req.Clone(req.Context())
+					r.Body = io.NopCloser(onlyReader{bytes.NewReader(payload)})
+					return r
+				})}
+			},
+			expectCall: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var bodies []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				bodies = append(bodies, string(body))
+				if len(bodies) == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			calls := 0
+			client := NewClient(newTestRetry(3), tc.opts(&calls)...)
+			req, _ := http.NewRequest(http.MethodPost, server.URL, bytes.NewReader(payload))
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			resp.Body.Close()
+
+			if !slices.Equal(bodies, []string{string(payload), string(payload)}) {
+				t.Errorf("expected the payload twice, got %q", bodies)
+			}
+			if calls != tc.expectCall {
+				t.Errorf("expected %d cloner calls, got %d", tc.expectCall, calls)
+			}
+		})
+	}
+}
+
+// TestWithHTTPRequestTimeout verifies that a hanging attempt times out and
+// is retried, while the configured client is left unchanged.
+func TestWithHTTPRequestTimeout(t *testing.T) {
+	t.Parallel()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			// The first request hangs until the client gives up.
+			<-r.Context().Done()
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	base := &http.Client{Transport: http.DefaultTransport, Timeout: time.Hour}
+	client := NewClient(newTestRetry(3),
+		WithHTTPClientOverride(func(int) *http.Client { return base }),
+		WithHTTPRequestTimeout(50*time.Millisecond),
+	)
+
+	start := time.Now()
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected 2 requests, got %d", got)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the first attempt to time out quickly, took %v", elapsed)
+	}
+	if base.Timeout != time.Hour {
+		t.Errorf("expected the configured client to keep its timeout, got %v", base.Timeout)
+	}
+}
+
+// TestWithHTTPResponseValidator verifies that responses rejected by the
+// validator are retried or aborted and that the body stays readable.
+func TestWithHTTPResponseValidator(t *testing.T) {
+	t.Parallel()
+	errNotReady := errors.New("not ready")
+
+	testCases := []struct {
+		name         string
+		reject       func(body string) error
+		wantRequests int32
+		wantErr      bool
+	}{
+		{
+			name: "retryable error retries",
+			reject: func(body string) error {
+				if body != "ready" {
+					return errNotReady
+				}
+				return nil
+			},
+			wantRequests: 2,
+		},
+		{
+			name: "non-retryable error aborts",
+			reject: func(body string) error {
+				return retry.NonRetryable(errNotReady)
+			},
+			wantRequests: 1,
+			wantErr:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if requests.Add(1) == 1 {
+					io.WriteString(w, "pending")
+					return
+				}
+				io.WriteString(w, "ready")
+			}))
+			defer server.Close()
+
+			client := NewClient(newTestRetry(3), WithHTTPResponseValidator(func(resp *http.Response) error {
+				body, err := io.ReadAll(resp.Body)
+				if err != nil {
+					return err
+				}
+				return tc.reject(string(body))
+			}))
+
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			resp, err := client.Do(req)
+			if got := requests.Load(); got != tc.wantRequests {
+				t.Errorf("expected %d requests, got %d", tc.wantRequests, got)
+			}
+			if tc.wantErr {
+				if err == nil || !strings.Contains(err.Error(), errNotReady.Error()) {
+					t.Errorf("expected an error mentioning %q, got %v", errNotReady, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			defer resp.Body.Close()
+
+			if body, _ := io.ReadAll(resp.Body); string(body) != "ready" {
+				t.Errorf("expected the body to be readable after validation, got %q", body)
+			}
+		})
+	}
+}
+
+// TestWithHTTPProxy verifies that every attempt is sent through the proxy
+// selected for it.
+func TestWithHTTPProxy(t *testing.T) {
+	t.Parallel()
+	var hits []string
+	var mu sync.Mutex
+	newProxy := func(name string, status int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			hits = append(hits, name+" "+r.URL.String())
+			mu.Unlock()
+			w.WriteHeader(status)
+		}))
+	}
+	proxy1 := newProxy("proxy1", http.StatusServiceUnavailable)
+	defer proxy1.Close()
+	proxy2 := newProxy("proxy2", http.StatusOK)
+	defer proxy2.Close()
+
+	proxies := []*url.URL{mustParseURL(t, proxy1.URL), mustParseURL(t, proxy2.URL)}
+	client := NewClient(newTestRetry(3), WithHTTPProxy(func(attempt int) *url.URL {
+		return proxies[(attempt-1)%len(proxies)]
+	}))
+
+	req, _ := http.NewRequest(http.MethodGet, "http://upstream.invalid/data", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	expected := []string{"proxy1 http://upstream.invalid/data", "proxy2 http://upstream.invalid/data"}
+	if !slices.Equal(hits, expected) {
+		t.Errorf("expected proxies to be used in order %v, got %v", expected, hits)
+	}
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+// TestWithHTTPTLSConfig verifies that every attempt uses the TLS
+// configuration selected for it.
+func TestWithHTTPTLSConfig(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	untrusted := &tls.Config{}
+	trusted := server.Client().Transport.(*http.Transport).TLSClientConfig
+
+	var used []*tls.Config
+	client := NewClient(newTestRetry(3), WithHTTPTLSConfig(func(attempt int) *tls.Config {
+		cfg := untrusted
+		if attempt > 1 {
+			cfg = trusted
+		}
+		used = append(used, cfg)
+		return cfg
+	}))
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if len(used) != 2 || used[0] != untrusted || used[1] != trusted {
+		t.Errorf("expected the untrusted then the trusted configuration, got %v", used)
+	}
+	if len(client.tlsTransports) != 2 {
+		t.Errorf("expected one transport per configuration, got %d", len(client.tlsTransports))
+	}
+}
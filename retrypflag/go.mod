@@ -0,0 +1,8 @@
+module github.com/1amDudman/try-again-go/retrypflag
+
+go 1.24.4
+
+require (
+	github.com/1amDudman/try-again-go v0.1.0
+	github.com/spf13/pflag v1.0.10
+)
@@ -0,0 +1,72 @@
+// Package retrypflag registers retry settings as command-line flags using
+// github.com/spf13/pflag, as used by cobra-based CLIs. It lives in its own
+// module so that the pflag dependency is only pulled in by users who need
+// it.
+package retrypflag
+
+import (
+	"time"
+
+	retry "github.com/1amDudman/try-again-go"
+	"github.com/spf13/pflag"
+)
+
+// flagName returns the name of the flag for the given setting.
+func flagName(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "-" + name
+}
+
+// AddRetryFlags registers the following flags on fs, with the defaults of
+// retry.NewRetry:
+//   - --<prefix>-attempts: number of attempts
+//   - --<prefix>-base-delay: base delay between attempts
+//   - --<prefix>-max-delay: maximum delay between attempts
+//
+// With an empty prefix the flags are named --attempts, --base-delay and
+// --max-delay.
+//
+// Example:
+//
+//	retrypflag.AddRetryFlags(cmd.Flags(), "upload")
+func AddRetryFlags(fs *pflag.FlagSet, prefix string) {
+	fs.Int(flagName(prefix, "attempts"), 3, "number of retry attempts")
+	fs.Duration(flagName(prefix, "base-delay"), 100*time.Millisecond, "base delay between retry attempts")
+	fs.Duration(flagName(prefix, "max-delay"), time.Second, "maximum delay between retry attempts")
+}
+
+// NewRetryFromFlags creates a RetryConfig from the flags registered with
+// AddRetryFlags. Call it after fs has been parsed. The configuration is
+// validated; invalid values return an error wrapping retry.ErrInvalidConfig.
+//
+// Example:
+//
+//	RunE: func(cmd *cobra.Command, args []string) error {
+//	    rc, err := retrypflag.NewRetryFromFlags(cmd.Flags(), "upload")
+//	    if err != nil {
+//	        return err
+//	    }
+//	    return retry.DoVoid(cmd.Context(), rc, upload)
+//	}
+func NewRetryFromFlags(fs *pflag.FlagSet, prefix string) (*retry.RetryConfig, error) {
+	attempts, err := fs.GetInt(flagName(prefix, "attempts"))
+	if err != nil {
+		return nil, err
+	}
+	baseDelay, err := fs.GetDuration(flagName(prefix, "base-delay"))
+	if err != nil {
+		return nil, err
+	}
+	maxDelay, err := fs.GetDuration(flagName(prefix, "max-delay"))
+	if err != nil {
+		return nil, err
+	}
+
+	return retry.NewRetryBuilder().
+		Attempts(attempts).
+		Delay(baseDelay).
+		MaxDelay(maxDelay).
+		Build()
+}
@@ -0,0 +1,78 @@
+package retrypflag
+
+import (
+	"errors"
+	"testing"
+
+	retry "github.com/1amDudman/try-again-go"
+	"github.com/spf13/pflag"
+)
+
+// TestNewRetryFromFlags verifies that parsed flag values are applied and
+// unset flags keep the defaults.
+func TestNewRetryFromFlags(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		prefix   string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "all flags",
+			prefix:   "upload",
+			args:     []string{"--upload-attempts=5", "--upload-base-delay=200ms", "--upload-max-delay=5s"},
+			expected: "attempts=5 baseDelay=200ms maxDelay=5s strategy=FixedDelay onRetry=false",
+		},
+		{
+			name:     "defaults",
+			prefix:   "upload",
+			args:     nil,
+			expected: "attempts=3 baseDelay=100ms maxDelay=1s strategy=FixedDelay onRetry=false",
+		},
+		{
+			name:     "empty prefix",
+			prefix:   "",
+			args:     []string{"--attempts", "7"},
+			expected: "attempts=7 baseDelay=100ms maxDelay=1s strategy=FixedDelay onRetry=false",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			AddRetryFlags(fs, tc.prefix)
+			if err := fs.Parse(tc.args); err != nil {
+				t.Fatal(err)
+			}
+
+			rc, err := NewRetryFromFlags(fs, tc.prefix)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if got := rc.String(); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestNewRetryFromFlagsErrors verifies that invalid values and missing
+// flags are reported.
+func TestNewRetryFromFlagsErrors(t *testing.T) {
+	t.Parallel()
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	AddRetryFlags(fs, "upload")
+	if err := fs.Parse([]string{"--upload-attempts=0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewRetryFromFlags(fs, "upload"); !errors.Is(err, retry.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig, got %v", err)
+	}
+	if _, err := NewRetryFromFlags(fs, "download"); err == nil {
+		t.Error("expected an error for unregistered flags")
+	}
+}
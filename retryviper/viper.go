@@ -0,0 +1,71 @@
+// Package retryviper reads retry policies from configuration files using
+// github.com/spf13/viper. It lives in its own module so that the Viper
+// dependencies are only pulled in by users who need them.
+package retryviper
+
+import (
+	"fmt"
+
+	retry "github.com/1amDudman/try-again-go"
+	"github.com/spf13/viper"
+)
+
+// strategies maps the values of the strategy key to delay strategies.
+var strategies = map[string]func() retry.DelayTypeFunc{
+	"fixed":       retry.FixedDelay,
+	"exponential": retry.ExpBackoffWithJitter,
+}
+
+// NewRetryFromViper creates a RetryConfig from the keys below prefix in v.
+// Keys that are not set keep the defaults of retry.NewRetry:
+//   - <prefix>.attempts: number of attempts, e.g. 5
+//   - <prefix>.base_delay: base delay, e.g. "200ms"
+//   - <prefix>.max_delay: maximum delay, e.g. "5s"
+//   - <prefix>.strategy: "fixed" or "exponential"
+//
+// The extra options are applied after the configured values, so they can
+// add settings that cannot be expressed in a file, such as a logger. The
+// resulting configuration is validated; errors wrap retry.ErrInvalidConfig.
+//
+// Example:
+//
+//	# config.yaml
+//	retry:
+//	  attempts: 5
+//	  base_delay: 200ms
+//	  max_delay: 5s
+//	  strategy: exponential
+//
+//	rc, err := retryviper.NewRetryFromViper(viper.GetViper(), "retry",
+//	    retry.WithLogger(log.Default()),
+//	)
+func NewRetryFromViper(v *viper.Viper, prefix string, extra ...retry.Option) (*retry.RetryConfig, error) {
+	key := func(name string) string {
+		if prefix == "" {
+			return name
+		}
+		return prefix + "." + name
+	}
+
+	b := retry.NewRetryBuilder()
+
+	if v.IsSet(key("attempts")) {
+		b.Attempts(v.GetInt(key("attempts")))
+	}
+	if v.IsSet(key("base_delay")) {
+		b.Delay(v.GetDuration(key("base_delay")))
+	}
+	if v.IsSet(key("max_delay")) {
+		b.MaxDelay(v.GetDuration(key("max_delay")))
+	}
+	if v.IsSet(key("strategy")) {
+		name := v.GetString(key("strategy"))
+		strategy, ok := strategies[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown strategy %q in %s", retry.ErrInvalidConfig, name, key("strategy"))
+		}
+		b.DelayType(strategy())
+	}
+
+	return b.With(extra...).Build()
+}
@@ -0,0 +1,115 @@
+package retryviper
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	retry "github.com/1amDudman/try-again-go"
+	"github.com/spf13/viper"
+)
+
+// newViper creates an in-memory Viper instance from a YAML document.
+func newViper(t *testing.T, yaml string) *viper.Viper {
+	t.Helper()
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(strings.NewReader(yaml)); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+// TestNewRetryFromViper verifies that configured keys are applied and unset
+// keys keep their defaults.
+func TestNewRetryFromViper(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		yaml     string
+		prefix   string
+		expected string
+	}{
+		{
+			name: "all keys",
+			yaml: `
+retry:
+  attempts: 5
+  base_delay: 200ms
+  max_delay: 5s
+  strategy: exponential
+`,
+			prefix:   "retry",
+			expected: "attempts=5 baseDelay=200ms maxDelay=5s strategy=ExpBackoffWithJitter onRetry=false",
+		},
+		{
+			name: "nested prefix with defaults",
+			yaml: `
+services:
+  billing:
+    attempts: 7
+`,
+			prefix:   "services.billing",
+			expected: "attempts=7 baseDelay=100ms maxDelay=1s strategy=FixedDelay onRetry=false",
+		},
+		{
+			name:     "empty prefix",
+			yaml:     "attempts: 2\nstrategy: fixed\n",
+			prefix:   "",
+			expected: "attempts=2 baseDelay=100ms maxDelay=1s strategy=FixedDelay onRetry=false",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rc, err := NewRetryFromViper(newViper(t, tc.yaml), tc.prefix)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if got := rc.String(); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestNewRetryFromViperExtraOptions verifies that extra options are applied
+// after the configured values.
+func TestNewRetryFromViperExtraOptions(t *testing.T) {
+	t.Parallel()
+	v := newViper(t, "retry:\n  attempts: 5\n")
+
+	rc, err := NewRetryFromViper(v, "retry", retry.WithAttempts(9))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(rc.String(), "attempts=9 ") {
+		t.Errorf("expected extra option to win, got %q", rc.String())
+	}
+}
+
+// TestNewRetryFromViperInvalid verifies that invalid values are rejected.
+func TestNewRetryFromViperInvalid(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		yaml string
+	}{
+		{"unknown strategy", "retry:\n  strategy: linear\n"},
+		{"zero attempts", "retry:\n  attempts: 0\n"},
+		{"negative delay", "retry:\n  base_delay: -1s\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := NewRetryFromViper(newViper(t, tc.yaml), "retry")
+			if !errors.Is(err, retry.ErrInvalidConfig) {
+				t.Errorf("expected ErrInvalidConfig, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package retry
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// sessionVersion is the version of the binary session encoding. Version 1
+// did not record completion; it is still decoded.
+const sessionVersion = 2
+
+// RetrySession tracks the progress of a long-running retry loop so that it
+// can be persisted and resumed after a process restart. It records the
+// number of attempts made, the last error and the elapsed time.
+//
+// Use NewRetrySession() to start a new session, MarshalBinary() to persist
+// it, and ResumeSession() to restore it. A RetrySession is safe for
+// concurrent use, so it may be persisted from another goroutine while Do is
+// running.
+type RetrySession struct {
+	rc *RetryConfig // Retry policy of the session
+
+	mu       sync.Mutex
+	attempts int           // Number of attempts made so far
+	lastErr  error         // Error of the last attempt, nil after success
+	elapsed  time.Duration // Time spent in Do, accumulated across restarts
+	done     bool          // The operation succeeded
+}
+
+// NewRetrySession creates a session that starts from the first attempt.
+func NewRetrySession(rc *RetryConfig) *RetrySession {
+	return &RetrySession{rc: rc}
+}
+
+// ResumeSession restores a session from state produced by MarshalBinary.
+// Only the message of the last error survives serialization; its type and
+// wrapped errors are lost.
+//
+// Example:
+//
+//	state, _ := os.ReadFile("export.retry")
+//	session, err := retry.ResumeSession(rc, state)
+//	if err != nil {
+//	    return err
+//	}
+//	err = session.Do(ctx, runExport)
+func ResumeSession(rc *RetryConfig, state []byte) (*RetrySession, error) {
+	s := NewRetrySession(rc)
+	if err := s.UnmarshalBinary(state); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Attempts returns the number of attempts made so far.
+func (s *RetrySession) Attempts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.attempts
+}
+
+// LastError returns the error of the last attempt, or nil if it succeeded.
+func (s *RetrySession) LastError() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastErr
+}
+
+// Elapsed returns the time spent in Do, accumulated across restarts.
+func (s *RetrySession) Elapsed() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.elapsed
+}
+
+// Do runs fn with the session's retry policy, continuing with the attempt
+// after the last one recorded. Delays are calculated for the continued
+// attempt numbers, so backoff picks up where it left off. Once the operation
+// succeeded, Do returns nil without calling fn again. If the budget was
+// already used up before the restart, Do returns an *ExhaustedError with
+// the last error without calling fn.
+func (s *RetrySession) Do(ctx context.Context, fn func() error) error {
+	s.mu.Lock()
+	first := s.attempts + 1
+	lastErr := s.lastErr
+	done := s.done
+	s.mu.Unlock()
+
+	if done {
+		return nil
+	}
+
+	if first > s.rc.maxAttempts() {
+		var errs []error
+		if lastErr != nil {
+			errs = []error{lastErr}
+		}
+		return s.rc.exhaustedError(first-1, errs, s.Elapsed())
+	}
+
+	obs := &sessionObserver{session: s, start: s.rc.clock.Now(), base: s.Elapsed()}
+	_, err := do(ctx, s.rc, func() (struct{}, error) {
+		return struct{}{}, fn()
+	}, loopState{first: first, observer: obs})
+	obs.update()
+
+	if err == nil {
+		s.mu.Lock()
+		s.done = true
+		s.mu.Unlock()
+	}
+
+	return err
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (s *RetrySession) MarshalBinary() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var msg string
+	if s.lastErr != nil {
+		msg = s.lastErr.Error()
+	}
+
+	var done byte
+	if s.done {
+		done = 1
+	}
+
+	buf := []byte{sessionVersion, done}
+	buf = binary.AppendUvarint(buf, uint64(s.attempts))
+	buf = binary.AppendVarint(buf, int64(s.elapsed))
+	buf = binary.AppendUvarint(buf, uint64(len(msg)))
+	buf = append(buf, msg...)
+
+	return buf, nil
+}
+
+// errInvalidSession is returned when session state cannot be decoded.
+var errInvalidSession = errors.New("invalid retry session state")
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *RetrySession) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || (data[0] != 1 && data[0] != sessionVersion) {
+		return fmt.Errorf("%w: unsupported version", errInvalidSession)
+	}
+	version := data[0]
+	data = data[1:]
+
+	// Version 1 sessions are complete if an attempt was made without
+	// error.
+	var done bool
+	if version == sessionVersion {
+		if len(data) == 0 || data[0] > 1 {
+			return fmt.Errorf("%w: completion", errInvalidSession)
+		}
+		done = data[0] == 1
+		data = data[1:]
+	}
+
+	attempts, n := binary.Uvarint(data)
+	if n <= 0 {
+		return fmt.Errorf("%w: attempts", errInvalidSession)
+	}
+	data = data[n:]
+
+	elapsed, n := binary.Varint(data)
+	if n <= 0 {
+		return fmt.Errorf("%w: elapsed time", errInvalidSession)
+	}
+	data = data[n:]
+
+	msgLen, n := binary.Uvarint(data)
+	if n <= 0 || uint64(len(data[n:])) != msgLen {
+		return fmt.Errorf("%w: last error", errInvalidSession)
+	}
+	msg := string(data[n:])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attempts = int(attempts)
+	s.elapsed = time.Duration(elapsed)
+	s.lastErr = nil
+	if msg != "" {
+		s.lastErr = errors.New(msg)
+	}
+	s.done = done || (version == 1 && attempts > 0 && msg == "")
+
+	return nil
+}
+
+// sessionObserver keeps a RetrySession up to date while Do is running.
+type sessionObserver struct {
+	session *RetrySession
+	start   time.Time     // When the current Do call started
+	base    time.Duration // Elapsed time before the current Do call
+}
+
+// record implements observer.
+func (o *sessionObserver) record(kind TraceEventKind, attempt int, err error, _ time.Duration) {
+	if kind == EventAttemptResult {
+		o.session.mu.Lock()
+		o.session.attempts = attempt
+		o.session.lastErr = err
+		o.session.mu.Unlock()
+	}
+
+	o.update()
+}
+
+// update refreshes the elapsed time of the session.
+func (o *sessionObserver) update() {
+	o.session.mu.Lock()
+	defer o.session.mu.Unlock()
+	o.session.elapsed = o.base + o.session.rc.clock.Now().Sub(o.start)
+}
@@ -0,0 +1,179 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestRetrySessionResume verifies that a persisted session continues with
+// the next attempt and keeps the accumulated state.
+func TestRetrySessionResume(t *testing.T) {
+	t.Parallel()
+	var attempts []int
+	rc := NewRetry(
+		WithAttempts(5),
+		WithDelay(time.Millisecond),
+		WithOnRetry(func(attempt int, _ error, _ time.Duration) { attempts = append(attempts, attempt) }),
+	)
+
+	// The first process makes two attempts and is then stopped.
+	ctx, cancel := context.WithCancel(context.Background())
+	session := NewRetrySession(rc)
+	calls := 0
+	_ = session.Do(ctx, func() error {
+		calls++
+		if calls == 2 {
+			cancel()
+		}
+		return errors.New("export failed")
+	})
+
+	state, err := session.MarshalBinary()
+	if err != nil {
+		t.Fatalf("expected no error marshaling, got %v", err)
+	}
+
+	// The restarted process resumes the session.
+	resumed, err := ResumeSession(rc, state)
+	if err != nil {
+		t.Fatalf("expected no error resuming, got %v", err)
+	}
+
+	if resumed.Attempts() != 2 {
+		t.Errorf("expected 2 recorded attempts, got %d", resumed.Attempts())
+	}
+
+	if resumed.LastError() == nil || resumed.LastError().Error() != "export failed" {
+		t.Errorf("expected last error to be restored, got %v", resumed.LastError())
+	}
+
+	if resumed.Elapsed() <= 0 || resumed.Elapsed() != session.Elapsed() {
+		t.Errorf("expected elapsed time %v to be restored, got %v", session.Elapsed(), resumed.Elapsed())
+	}
+
+	calls = 0
+	err = resumed.Do(context.Background(), func() error {
+		calls++
+		return errors.New("export failed")
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if calls != 3 {
+		t.Errorf("expected the remaining 3 attempts, got %d", calls)
+	}
+
+	if resumed.Attempts() != 5 {
+		t.Errorf("expected 5 recorded attempts, got %d", resumed.Attempts())
+	}
+
+	if attempts[len(attempts)-1] != 4 {
+		t.Errorf("expected attempt numbers to continue, got %v", attempts)
+	}
+}
+
+// TestRetrySessionExhausted verifies that a resumed session whose budget is
+// exhausted returns the last error without calling the function.
+func TestRetrySessionExhausted(t *testing.T) {
+	t.Parallel()
+	rc := NewRetry(WithAttempts(2), WithDelay(time.Millisecond))
+	session := NewRetrySession(rc)
+	_ = session.Do(context.Background(), func() error { return errors.New("export failed") })
+
+	state, _ := session.MarshalBinary()
+	resumed, _ := ResumeSession(rc, state)
+
+	called := false
+	err := resumed.Do(context.Background(), func() error {
+		called = true
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if called {
+		t.Error("expected function not to be called")
+	}
+}
+
+// TestResumeSessionInvalidState verifies that corrupt state is rejected.
+func TestResumeSessionInvalidState(t *testing.T) {
+	t.Parallel()
+	rc := NewRetry()
+
+	for _, state := range [][]byte{nil, {0}, {sessionVersion}, {sessionVersion, 1, 2, 5, 'a'}} {
+		if _, err := ResumeSession(rc, state); !errors.Is(err, errInvalidSession) {
+			t.Errorf("expected invalid session error for %v, got %v", state, err)
+		}
+	}
+}
+
+// TestRetrySessionCompleted verifies that a session that succeeded does not
+// call the function again, whether or not budget remains, also after it was
+// persisted.
+func TestRetrySessionCompleted(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		attempts int
+		failures int
+	}{
+		{name: "budget left", attempts: 5, failures: 1},
+		{name: "budget used up", attempts: 2, failures: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rc := NewRetry(WithAttempts(tc.attempts), WithDelay(time.Millisecond))
+			session := NewRetrySession(rc)
+			calls := 0
+			fn := func() error {
+				calls++
+				if calls <= tc.failures {
+					return errors.New("export failed")
+				}
+				return nil
+			}
+
+			if err := session.Do(context.Background(), fn); err != nil {
+				t.Fatalf("expected success, got %v", err)
+			}
+
+			state, _ := session.MarshalBinary()
+			resumed, _ := ResumeSession(rc, state)
+			for _, s := range []*RetrySession{session, resumed} {
+				if err := s.Do(context.Background(), fn); err != nil {
+					t.Errorf("expected nil after success, got %v", err)
+				}
+			}
+
+			if calls != tc.failures+1 {
+				t.Errorf("expected %d calls, got %d", tc.failures+1, calls)
+			}
+		})
+	}
+}
+
+// TestResumeSessionVersion1 verifies that sessions persisted before
+// completion was recorded are still restored.
+func TestResumeSessionVersion1(t *testing.T) {
+	t.Parallel()
+	rc := NewRetry(WithAttempts(5), WithDelay(time.Millisecond))
+
+	// Version 1, 2 attempts, no elapsed time, no error: completed.
+	resumed, err := ResumeSession(rc, []byte{1, 2, 0, 0})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	called := false
+	if err := resumed.Do(context.Background(), func() error { called = true; return nil }); err != nil || called {
+		t.Errorf("expected the completed session not to run again, got %v, called %v", err, called)
+	}
+}
@@ -0,0 +1,54 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrSignalled is returned by DoUntilSignalled when the done channel was
+// closed before the operation succeeded.
+var ErrSignalled = errors.New("retry stopped by signal")
+
+// DoUntilSignalled executes fn like Do, but additionally stops retrying as
+// soon as done is closed. This is useful when several workers compete for
+// the same result and the first one to succeed should stop the others. An
+// attempt that is already running is not interrupted, as fn does not receive
+// the context; if it succeeds, its result is returned.
+//
+// When done is closed before fn succeeds, the returned error wraps
+// ErrSignalled together with the error that ended the retry loop.
+//
+// Example:
+//
+//	done := make(chan struct{})
+//	var once sync.Once
+//	for _, worker := range workers {
+//	    go func() {
+//	        result, err := retry.DoUntilSignalled(ctx, rc, worker.Run, done)
+//	        if err == nil {
+//	            once.Do(func() { close(done) })
+//	            publish(result)
+//	        }
+//	    }()
+//	}
+func DoUntilSignalled[T any](ctx context.Context, rc *RetryConfig, fn RetryFunc[T], done <-chan struct{}) (T, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	go func() {
+		select {
+		case <-done:
+			cancel(ErrSignalled)
+		case <-ctx.Done():
+		}
+	}()
+
+	data, err := Do(ctx, rc, fn)
+	if err != nil && errors.Is(context.Cause(ctx), ErrSignalled) {
+		var zero T
+		return zero, fmt.Errorf("%w: %w", ErrSignalled, err)
+	}
+
+	return data, err
+}
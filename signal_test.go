@@ -0,0 +1,83 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestDoUntilSignalledStopsOnDone verifies that closing the done channel
+// ends the retry loop with ErrSignalled.
+func TestDoUntilSignalledStopsOnDone(t *testing.T) {
+	t.Parallel()
+	rc := NewRetry(WithAttempts(100), WithDelay(10*time.Millisecond))
+	done := make(chan struct{})
+
+	var calls atomic.Int32
+	fn := func() (int, error) {
+		if calls.Add(1) == 3 {
+			close(done)
+		}
+		return 0, errors.New("not yet")
+	}
+
+	_, err := DoUntilSignalled(context.Background(), rc, fn, done)
+	if !errors.Is(err, ErrSignalled) {
+		t.Fatalf("expected ErrSignalled, got %v", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("expected 3 calls, got %d", got)
+	}
+}
+
+// TestDoUntilSignalledCompetingWorkers verifies that the first worker to
+// succeed stops the others.
+func TestDoUntilSignalledCompetingWorkers(t *testing.T) {
+	t.Parallel()
+	rc := NewRetry(WithAttempts(1000), WithDelay(time.Millisecond))
+	done := make(chan struct{})
+
+	winner := func() (string, error) {
+		return "winner", nil
+	}
+	loser := func() (string, error) {
+		return "", errors.New("always fails")
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := DoUntilSignalled(context.Background(), rc, loser, done)
+		errs <- err
+	}()
+
+	result, err := DoUntilSignalled(context.Background(), rc, winner, done)
+	if err != nil || result != "winner" {
+		t.Fatalf("expected winner without error, got %q, %v", result, err)
+	}
+	close(done)
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, ErrSignalled) {
+			t.Errorf("expected ErrSignalled for the losing worker, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("losing worker did not stop after done was closed")
+	}
+}
+
+// TestDoUntilSignalledNormalExit verifies that the usual exit conditions
+// still apply when done is never closed.
+func TestDoUntilSignalledNormalExit(t *testing.T) {
+	t.Parallel()
+	rc := NewRetry(WithAttempts(2), WithDelay(time.Millisecond))
+
+	_, err := DoUntilSignalled(context.Background(), rc, func() (int, error) {
+		return 0, errors.New("fail")
+	}, make(chan struct{}))
+	if !errors.Is(err, ErrExhausted) || errors.Is(err, ErrSignalled) {
+		t.Errorf("expected ErrExhausted without ErrSignalled, got %v", err)
+	}
+}
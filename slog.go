@@ -0,0 +1,49 @@
+package retry
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WithSlog sets a structured logger for retry events. In addition to the
+// Printf-style Logger, Do then emits log/slog records with the following
+// attributes instead of formatted strings:
+//   - attempt (int): the current attempt number
+//   - max_attempts (int): the configured number of attempts
+//   - delay (duration): the delay before the next attempt, if any
+//   - error (error): the error of the attempt
+//   - strategy (string): the name of the delay strategy
+//   - request_id (string): the ID set with WithRequestID, if any
+//
+// Records are emitted with slog.Logger.LogAttrs, which avoids allocations
+// when the level is disabled. Failed attempts that are retried are logged at
+// Warn level, final failures at Error level.
+//
+// Example:
+//
+//	retry.NewRetry(retry.WithSlog(slog.Default()))
+func WithSlog(logger *slog.Logger) Option {
+	return func(rc *RetryConfig) {
+		rc.slog = logger
+	}
+}
+
+// logAttrs emits a structured retry event if a slog logger is configured.
+func (rc *RetryConfig) logAttrs(ctx context.Context, level slog.Level, msg string,
+	attempt, maxAttempts int, delay time.Duration, err error) {
+	if rc.slog == nil || !rc.slog.Enabled(ctx, level) {
+		return
+	}
+	if maxAttempts == unlimitedAttempts {
+		maxAttempts = 0
+	}
+
+	rc.slog.LogAttrs(ctx, level, msg, append([]slog.Attr{
+		slog.Int("attempt", attempt),
+		slog.Int("max_attempts", maxAttempts),
+		slog.Duration("delay", delay),
+		slog.Any("error", err),
+		slog.String("strategy", funcName(rc.delayType)),
+	}, rc.requestIDAttrs()...)...)
+}
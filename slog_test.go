@@ -0,0 +1,58 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDoSlog verifies that Do emits structured records carrying all retry
+// attributes.
+func TestDoSlog(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	rc := NewRetry(
+		WithAttempts(2),
+		WithDelay(5*time.Millisecond),
+		WithDelayType(ExpBackoffWithJitter()),
+		WithSlog(logger),
+	)
+
+	_, _ = Do(context.Background(), rc, func() (string, error) {
+		return "", errors.New("attempt error")
+	})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 records, got %d:\n%s", len(lines), buf.String())
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("expected JSON record, got %v", err)
+	}
+
+	for _, key := range []string{"attempt", "max_attempts", "delay", "error", "strategy"} {
+		if _, ok := record[key]; !ok {
+			t.Errorf("expected attribute %q in record %s", key, lines[0])
+		}
+	}
+
+	if record["level"] != "WARN" || record["attempt"] != float64(1) || record["max_attempts"] != float64(2) {
+		t.Errorf("unexpected record values: %s", lines[0])
+	}
+
+	if record["error"] != "attempt error" || record["strategy"] != "ExpBackoffWithJitter" {
+		t.Errorf("unexpected error or strategy: %s", lines[0])
+	}
+
+	if !strings.Contains(lines[1], `"level":"ERROR"`) || !strings.Contains(lines[1], "all attempts failed") {
+		t.Errorf("expected final error record, got %s", lines[1])
+	}
+}
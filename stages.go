@@ -0,0 +1,81 @@
+package retry
+
+import (
+	"slices"
+	"time"
+)
+
+// RetryStage is one phase of a multi-phase retry policy created with
+// NewRetryChain.
+type RetryStage struct {
+	DelayType   DelayTypeFunc // Delay strategy of the stage, nil means FixedDelay
+	MaxAttempts int           // Number of attempts made in this stage
+	BaseDelay   time.Duration // Base delay passed to DelayType, 0 means the configured base delay
+}
+
+// NewRetryChain creates a RetryConfig that runs through stages in order,
+// e.g. a few fast retries followed by a few slow ones. Each stage makes its
+// MaxAttempts attempts before the next one starts, so the total number of
+// attempts is the sum over all stages. The delay before an attempt is
+// calculated by the strategy of the stage the attempt belongs to, with the
+// attempt number counted from the start of that stage. Stages with a
+// non-positive MaxAttempts are ignored.
+//
+// The opts are applied first, so settings such as WithMaxDelay or WithLogger
+// apply to all stages; WithAttempts and WithDelayType are overridden.
+//
+// Example:
+//
+//	// 3 attempts 100ms apart, then 2 more attempts 5s apart.
+//	rc := retry.NewRetryChain([]retry.RetryStage{
+//	    {DelayType: retry.FixedDelay(), MaxAttempts: 3, BaseDelay: 100 * time.Millisecond},
+//	    {DelayType: retry.FixedDelay(), MaxAttempts: 2, BaseDelay: 5 * time.Second},
+//	}, retry.WithMaxDelay(10*time.Second))
+func NewRetryChain(stages []RetryStage, opts ...Option) *RetryConfig {
+	var active []RetryStage
+	total := 0
+	for _, stage := range stages {
+		if stage.MaxAttempts <= 0 {
+			continue
+		}
+		if stage.DelayType == nil {
+			stage.DelayType = FixedDelay()
+		}
+		active = append(active, stage)
+		total += stage.MaxAttempts
+	}
+
+	if len(active) == 0 {
+		return NewRetry(opts...)
+	}
+
+	return NewRetry(append(slices.Clone(opts), WithAttempts(total), WithDelayType(stagedDelay(active)))...)
+}
+
+// stagedDelay returns a DelayTypeFunc that dispatches the delay after the
+// given failed attempt to the stage of the next attempt.
+func stagedDelay(stages []RetryStage) DelayTypeFunc {
+	return func(attempt int, baseDelay, maxDelay time.Duration) time.Duration {
+		next := attempt + 1
+		first := 1 // Number of the first attempt of the stage
+		for i, stage := range stages {
+			if next < first+stage.MaxAttempts || i == len(stages)-1 {
+				// The first stage has no delay before its first attempt,
+				// so its delays are numbered from its second attempt.
+				local := next - first + 1
+				if i == 0 {
+					local = next - first
+				}
+
+				base := baseDelay
+				if stage.BaseDelay > 0 {
+					base = stage.BaseDelay
+				}
+				return stage.DelayType(local, base, maxDelay)
+			}
+			first += stage.MaxAttempts
+		}
+
+		return baseDelay
+	}
+}
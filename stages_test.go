@@ -0,0 +1,77 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestNewRetryChain verifies the total number of attempts and that each
+// delay is calculated by the stage of the following attempt, counting
+// attempts from the start of that stage.
+func TestNewRetryChain(t *testing.T) {
+	t.Parallel()
+	doubling := func(attempt int, baseDelay, maxDelay time.Duration) time.Duration {
+		return min(baseDelay<<(attempt-1), maxDelay)
+	}
+
+	var delays []time.Duration
+	rc := NewRetryChain([]RetryStage{
+		{DelayType: FixedDelay(), MaxAttempts: 3, BaseDelay: time.Millisecond},
+		{MaxAttempts: 0},
+		{DelayType: doubling, MaxAttempts: 3, BaseDelay: 2 * time.Millisecond},
+	}, WithDelay(time.Millisecond), WithMaxDelay(5*time.Millisecond), WithOnRetry(func(_ int, _ error, delay time.Duration) {
+		delays = append(delays, delay)
+	}))
+
+	calls := 0
+	_, err := Do(context.Background(), rc, func() (int, error) {
+		calls++
+		return 0, errors.New("fail")
+	})
+	if !errors.Is(err, ErrExhausted) {
+		t.Fatalf("expected ErrExhausted, got %v", err)
+	}
+
+	if calls != 6 {
+		t.Errorf("expected 6 attempts, got %d", calls)
+	}
+
+	expected := []time.Duration{
+		time.Millisecond, time.Millisecond, // Before attempts 2 and 3 of stage 1
+		2 * time.Millisecond, 4 * time.Millisecond, 5 * time.Millisecond, // Before attempts 1-3 of stage 2, capped
+	}
+	if !slices.Equal(delays, expected) {
+		t.Errorf("expected delays %v, got %v", expected, delays)
+	}
+}
+
+// TestNewRetryChainEmpty verifies that without usable stages the options
+// are applied unchanged.
+func TestNewRetryChainEmpty(t *testing.T) {
+	t.Parallel()
+	rc := NewRetryChain(nil, WithAttempts(4))
+
+	if rc.attempts != 4 {
+		t.Errorf("expected 4 attempts, got %d", rc.attempts)
+	}
+}
+
+// TestNewRetryChainKeepsOptions verifies that the options slice of the
+// caller is not modified, even when it has spare capacity.
+func TestNewRetryChainKeepsOptions(t *testing.T) {
+	t.Parallel()
+	opts := make([]Option, 1, 4)
+	opts[0] = WithMaxDelay(time.Second)
+	spare := opts[:cap(opts)]
+
+	NewRetryChain([]RetryStage{{MaxAttempts: 2}}, opts...)
+
+	for i, opt := range spare[1:] {
+		if opt != nil {
+			t.Errorf("expected the spare capacity to stay untouched, got an option at %d", i+1)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// RetryStats summarizes a retry loop. Unlike a Trace it keeps only
+// aggregates and the attempt errors, so it is cheap enough for production.
+type RetryStats struct {
+	Attempts   int           // Number of attempts that were started
+	TotalDelay time.Duration // Sum of the delays scheduled between attempts
+	Errors     []error       // Errors of the failed attempts, in order
+}
+
+// record implements observer by updating the statistics.
+func (s *RetryStats) record(kind TraceEventKind, _ int, err error, delay time.Duration) {
+	switch kind {
+	case EventAttemptStart:
+		s.Attempts++
+	case EventAttemptResult:
+		if err != nil && !isSuccess(err) && !isIgnored(err) {
+			s.Errors = append(s.Errors, err)
+		}
+	case EventDelayStart:
+		s.TotalDelay += delay
+	}
+}
+
+// DoWithStats creates a RetryConfig from opts, executes fn like Do and
+// additionally returns statistics of the retry loop. The statistics are
+// populated on success as well as on failure.
+//
+// Example:
+//
+//	user, stats, err := retry.DoWithStats(ctx, fetchUser, retry.WithAttempts(5))
+//	metrics.ObserveAttempts(stats.Attempts)
+func DoWithStats[T any](ctx context.Context, fn func() (T, error), opts ...Option) (T, RetryStats, error) {
+	stats := &RetryStats{}
+	data, err := do(ctx, NewRetry(opts...), fn, loopState{observer: stats})
+
+	return data, *stats, err
+}
+
+// RetryMetadata describes how a result of DoObserve was obtained.
+type RetryMetadata struct {
+	Attempts     int           // Number of attempts that were started
+	TotalElapsed time.Duration // Wall time of the whole retry loop
+	TotalDelay   time.Duration // Sum of the delays scheduled between attempts
+	LastError    error         // Error of the last failed attempt, nil if none failed
+	WasRetried   bool          // Whether more than one attempt was made
+}
+
+// record implements observer by updating the metadata.
+func (m *RetryMetadata) record(kind TraceEventKind, _ int, err error, delay time.Duration) {
+	switch kind {
+	case EventAttemptStart:
+		m.Attempts++
+		m.WasRetried = m.Attempts > 1
+	case EventAttemptResult:
+		if err != nil && !isSuccess(err) && !isIgnored(err) {
+			m.LastError = err
+		}
+	case EventDelayStart:
+		m.TotalDelay += delay
+	}
+}
+
+// DoObserve executes fn like Do and additionally returns metadata about the
+// retry loop, e.g. for logging whether a request needed retries. The
+// metadata is always populated, on success as well as on failure.
+//
+// Example:
+//
+//	user, meta, err := retry.DoObserve(ctx, rc, fetchUser)
+//	if meta.WasRetried {
+//	    log.Printf("fetched user after %d attempts in %v", meta.Attempts, meta.TotalElapsed)
+//	}
+func DoObserve[T any](ctx context.Context, rc *RetryConfig, fn func() (T, error)) (T, RetryMetadata, error) {
+	var meta RetryMetadata
+	start := rc.clock.Now()
+	data, err := do(ctx, rc, fn, loopState{observer: &meta})
+	meta.TotalElapsed = rc.clock.Now().Sub(start)
+
+	return data, meta, err
+}
@@ -0,0 +1,116 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestDoWithStats verifies that attempts, delays and errors are counted on
+// success as well as on failure.
+func TestDoWithStats(t *testing.T) {
+	t.Parallel()
+	errFail := errors.New("fail")
+
+	testCases := []struct {
+		name      string
+		failures  int
+		wantErr   bool
+		attempts  int
+		delay     time.Duration
+		errsCount int
+	}{
+		{name: "first attempt succeeds", failures: 0, attempts: 1, delay: 0, errsCount: 0},
+		{name: "success after retries", failures: 2, attempts: 3, delay: 2 * time.Millisecond, errsCount: 2},
+		{name: "exhausted", failures: 5, wantErr: true, attempts: 3, delay: 2 * time.Millisecond, errsCount: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			calls := 0
+			result, stats, err := DoWithStats(context.Background(), func() (int, error) {
+				calls++
+				if calls <= tc.failures {
+					return 0, errFail
+				}
+				return calls, nil
+			}, WithAttempts(3), WithDelay(time.Millisecond))
+
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if !tc.wantErr && result != calls {
+				t.Errorf("expected result %d, got %d", calls, result)
+			}
+			if stats.Attempts != tc.attempts {
+				t.Errorf("expected %d attempts, got %d", tc.attempts, stats.Attempts)
+			}
+			if stats.TotalDelay != tc.delay {
+				t.Errorf("expected total delay %v, got %v", tc.delay, stats.TotalDelay)
+			}
+			if len(stats.Errors) != tc.errsCount {
+				t.Errorf("expected %d errors, got %v", tc.errsCount, stats.Errors)
+			}
+			for _, e := range stats.Errors {
+				if !errors.Is(e, errFail) {
+					t.Errorf("expected recorded errors to be errFail, got %v", e)
+				}
+			}
+		})
+	}
+}
+
+// TestDoObserve verifies that the metadata is populated on first-attempt
+// success, after retries and on exhaustion.
+func TestDoObserve(t *testing.T) {
+	t.Parallel()
+	errFail := errors.New("fail")
+
+	testCases := []struct {
+		name       string
+		failures   int
+		wantErr    bool
+		attempts   int
+		delay      time.Duration
+		lastErr    error
+		wasRetried bool
+	}{
+		{name: "first attempt succeeds", failures: 0, attempts: 1},
+		{name: "success after retries", failures: 1, attempts: 2, delay: time.Millisecond, lastErr: errFail, wasRetried: true},
+		{name: "exhausted", failures: 5, wantErr: true, attempts: 3, delay: 2 * time.Millisecond, lastErr: errFail, wasRetried: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rc := NewRetry(WithAttempts(3), WithDelay(time.Millisecond))
+
+			calls := 0
+			_, meta, err := DoObserve(context.Background(), rc, func() (int, error) {
+				calls++
+				if calls <= tc.failures {
+					return 0, errFail
+				}
+				return calls, nil
+			})
+
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if meta.Attempts != tc.attempts || meta.WasRetried != tc.wasRetried {
+				t.Errorf("expected %d attempts and retried %v, got %+v", tc.attempts, tc.wasRetried, meta)
+			}
+			if meta.TotalDelay != tc.delay {
+				t.Errorf("expected total delay %v, got %v", tc.delay, meta.TotalDelay)
+			}
+			if !errors.Is(meta.LastError, tc.lastErr) {
+				t.Errorf("expected last error %v, got %v", tc.lastErr, meta.LastError)
+			}
+			if meta.TotalElapsed < meta.TotalDelay {
+				t.Errorf("expected elapsed time of at least %v, got %v", meta.TotalDelay, meta.TotalElapsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package retry
+
+import (
+	"math"
+	rand "math/rand/v2"
+	"time"
+)
+
+// ExpOption configures the exponential backoff used by composite delay
+// strategies such as ConstantThenExponential.
+type ExpOption func(*expConfig)
+
+// expConfig holds the parameters of an exponential backoff.
+type expConfig struct {
+	multiplier float64 // Growth factor between consecutive delays
+	jitter     float64 // Maximum random jitter as a fraction of the delay
+}
+
+// WithExpMultiplier sets the growth factor between consecutive delays of
+// the exponential phase. The default is 2.
+func WithExpMultiplier(multiplier float64) ExpOption {
+	return func(c *expConfig) {
+		c.multiplier = multiplier
+	}
+}
+
+// WithExpJitter sets the maximum random jitter added to each delay of the
+// exponential phase, as a fraction of the delay. The default is 0.2, the
+// same as ExpBackoffWithJitter; 0 disables jitter.
+func WithExpJitter(fraction float64) ExpOption {
+	return func(c *expConfig) {
+		c.jitter = fraction
+	}
+}
+
+// delay calculates baseDelay * multiplier^(n-1) plus jitter for the
+// n-th exponential delay, capped at maxDelay.
+func (c expConfig) delay(n int, baseDelay, maxDelay time.Duration) time.Duration {
+	exp := float64(baseDelay) * math.Pow(c.multiplier, float64(max(n-1, 0)))
+	if exp >= float64(maxDelay) {
+		return maxDelay
+	}
+
+	delay := time.Duration(exp)
+	if jitterMax := time.Duration(exp * c.jitter); jitterMax > 0 {
+		delay += time.Duration(rand.N(jitterMax))
+	}
+
+	return min(delay, maxDelay)
+}
+
+// ConstantThenExponential returns a DelayTypeFunc that waits constDelay
+// after each of the first constAttempts failed attempts and then switches
+// to exponential backoff with jitter, starting again at baseDelay. This
+// matches the common pattern "retry quickly a few times, then back off".
+//
+// Example delays with constAttempts=2, constDelay=10ms, baseDelay=100ms:
+//   - attempt 1: 10ms
+//   - attempt 2: 10ms
+//   - attempt 3: ~100-120ms
+//   - attempt 4: ~200-240ms
+//
+// Example:
+//
+//	retry.NewRetry(retry.WithDelayType(
+//	    retry.ConstantThenExponential(3, 10*time.Millisecond, retry.WithExpJitter(0.1)),
+//	))
+func ConstantThenExponential(constAttempts int, constDelay time.Duration, expOpts ...ExpOption) DelayTypeFunc {
+	cfg := expConfig{multiplier: 2, jitter: 0.2}
+	for _, opt := range expOpts {
+		opt(&cfg)
+	}
+
+	return func(attempt int, baseDelay, maxDelay time.Duration) time.Duration {
+		if attempt <= constAttempts {
+			return constDelay
+		}
+
+		return cfg.delay(attempt-constAttempts, baseDelay, maxDelay)
+	}
+}
+
+// ExpBackoffWithBase returns a DelayTypeFunc like ExpBackoffWithJitter that
+// multiplies the delay by multiplier instead of 2 after every attempt.
+// Further ExpOptions, such as WithExpJitter, customize it further.
+//
+// Example delays with multiplier=1.5, baseDelay=100ms:
+//   - attempt 1: ~100-120ms
+//   - attempt 2: ~150-180ms
+//   - attempt 3: ~225-270ms
+//
+// Example:
+//
+//	retry.NewRetry(retry.WithDelayType(retry.ExpBackoffWithBase(1.5)))
+func ExpBackoffWithBase(multiplier float64, expOpts ...ExpOption) DelayTypeFunc {
+	cfg := expConfig{multiplier: multiplier, jitter: 0.2}
+	for _, opt := range expOpts {
+		opt(&cfg)
+	}
+
+	return func(attempt int, baseDelay, maxDelay time.Duration) time.Duration {
+		return cfg.delay(attempt, baseDelay, maxDelay)
+	}
+}
@@ -0,0 +1,67 @@
+package retry
+
+import (
+	"testing"
+	"time"
+)
+
+// TestConstantThenExponentialTransition pins the delays around the switch
+// from the constant to the exponential phase.
+func TestConstantThenExponentialTransition(t *testing.T) {
+	t.Parallel()
+	delayFunc := ConstantThenExponential(2, 10*time.Millisecond, WithExpJitter(0))
+	baseDelay := 100 * time.Millisecond
+	maxDelay := 300 * time.Millisecond
+
+	testCases := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{1, 10 * time.Millisecond},
+		{2, 10 * time.Millisecond},
+		{3, 100 * time.Millisecond},
+		{4, 200 * time.Millisecond},
+		{5, 300 * time.Millisecond},
+		{100, 300 * time.Millisecond},
+	}
+
+	for _, tc := range testCases {
+		if got := delayFunc(tc.attempt, baseDelay, maxDelay); got != tc.expected {
+			t.Errorf("attempt %d: expected %v, got %v", tc.attempt, tc.expected, got)
+		}
+	}
+}
+
+// TestConstantThenExponentialOptions verifies the multiplier option and that
+// jitter stays within its bounds.
+func TestConstantThenExponentialOptions(t *testing.T) {
+	t.Parallel()
+	baseDelay := 100 * time.Millisecond
+
+	tripling := ConstantThenExponential(1, 0, WithExpMultiplier(3), WithExpJitter(0))
+	if got := tripling(3, baseDelay, time.Hour); got != 300*time.Millisecond {
+		t.Errorf("expected 300ms with multiplier 3, got %v", got)
+	}
+
+	jittered := ConstantThenExponential(1, 0)
+	for i := 0; i < 1000; i++ {
+		got := jittered(3, baseDelay, time.Hour)
+		if got < 200*time.Millisecond || got > 240*time.Millisecond {
+			t.Fatalf("expected delay between 200ms and 240ms, got %v", got)
+		}
+	}
+}
+
+// TestExpBackoffWithBase verifies that delays grow by the given multiplier
+// and are capped at the maximum delay.
+func TestExpBackoffWithBase(t *testing.T) {
+	t.Parallel()
+	delay := ExpBackoffWithBase(1.5, WithExpJitter(0))
+
+	expected := []time.Duration{100 * time.Millisecond, 150 * time.Millisecond, 225 * time.Millisecond, 300 * time.Millisecond}
+	for i, want := range expected {
+		if got := delay(i+1, 100*time.Millisecond, 300*time.Millisecond); got != want {
+			t.Errorf("attempt %d: expected %v, got %v", i+1, want, got)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// TelemetryProvider is a single integration point for tracing, metrics and
+// logging of retry loops. Implementations typically adapt OpenTelemetry or
+// a similar observability stack.
+type TelemetryProvider interface {
+	// StartSpan is called when a retry loop starts. The returned context
+	// is used for the rest of the loop, and the returned function is
+	// called with the final error, nil on success, when the loop ends.
+	StartSpan(ctx context.Context, name string) (context.Context, func(error))
+
+	// RecordAttempt is called once per finished attempt with its error,
+	// nil on success, and the delay before the next attempt, 0 if there
+	// is none.
+	RecordAttempt(ctx context.Context, attempt int, err error, delay time.Duration)
+
+	// RecordResult is called when the loop ends with the number of
+	// attempts made and whether the operation succeeded.
+	RecordResult(ctx context.Context, attempts int, success bool)
+}
+
+// WithTelemetryProvider sets a TelemetryProvider that receives spans,
+// attempts and results of every retry loop. It can be used instead of
+// combining WithLogger, WithSlog and WithOnRetry, but works alongside them.
+//
+// Example:
+//
+//	retry.NewRetry(retry.WithTelemetryProvider(otelretry.NewProvider(tracer, meter)))
+func WithTelemetryProvider(tp TelemetryProvider) Option {
+	return func(rc *RetryConfig) {
+		rc.telemetry = tp
+	}
+}
+
+// telemetrySpan reports the events of a single retry loop to a
+// TelemetryProvider. A nil *telemetrySpan discards all events.
+type telemetrySpan struct {
+	tp  TelemetryProvider
+	ctx context.Context
+	end func(error)
+
+	made    int   // Number of attempts made
+	attempt int   // Last finished attempt that was not reported yet
+	err     error // Error of that attempt
+	pending bool  // Whether attempt still has to be reported
+}
+
+// startTelemetry starts a span if a TelemetryProvider is configured and
+// replaces *ctx with the span context. It returns nil otherwise.
+func (rc *RetryConfig) startTelemetry(ctx *context.Context) *telemetrySpan {
+	if rc.telemetry == nil {
+		return nil
+	}
+
+	spanCtx, end := rc.telemetry.StartSpan(*ctx, "retry.Do")
+	*ctx = spanCtx
+	return &telemetrySpan{tp: rc.telemetry, ctx: spanCtx, end: end}
+}
+
+// attemptDone remembers a finished attempt. It is reported once the delay
+// before the next attempt is known.
+func (s *telemetrySpan) attemptDone(attempt int, err error) {
+	if s == nil {
+		return
+	}
+
+	s.made++
+	s.attempt, s.err, s.pending = attempt, err, true
+}
+
+// delay reports the pending attempt with the delay that follows it.
+func (s *telemetrySpan) delay(d time.Duration) {
+	if s == nil || !s.pending {
+		return
+	}
+
+	s.pending = false
+	s.tp.RecordAttempt(s.ctx, s.attempt, s.err, d)
+}
+
+// finish reports the pending attempt, if any, and the result of the loop,
+// and ends the span.
+func (s *telemetrySpan) finish(err error) {
+	if s == nil {
+		return
+	}
+
+	s.delay(0)
+	s.tp.RecordResult(s.ctx, s.made, err == nil)
+	if s.end != nil {
+		s.end(err)
+	}
+}
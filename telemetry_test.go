@@ -0,0 +1,89 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+type spanKey struct{}
+
+// recordingTelemetry is a TelemetryProvider that records all calls.
+type recordingTelemetry struct {
+	events []string
+	ended  []error
+}
+
+func (r *recordingTelemetry) StartSpan(ctx context.Context, name string) (context.Context, func(error)) {
+	r.events = append(r.events, "start "+name)
+	return context.WithValue(ctx, spanKey{}, name), func(err error) {
+		r.ended = append(r.ended, err)
+	}
+}
+
+func (r *recordingTelemetry) RecordAttempt(ctx context.Context, attempt int, err error, delay time.Duration) {
+	r.events = append(r.events, fmt.Sprintf("attempt %d err=%v delay=%v span=%v", attempt, err, delay, ctx.Value(spanKey{})))
+}
+
+func (r *recordingTelemetry) RecordResult(_ context.Context, attempts int, success bool) {
+	r.events = append(r.events, fmt.Sprintf("result attempts=%d success=%t", attempts, success))
+}
+
+// TestWithTelemetryProvider verifies the order and content of the reported
+// events for successful and failed loops.
+func TestWithTelemetryProvider(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name      string
+		succeedOn int
+		expected  []string
+	}{
+		{
+			name:      "success on second attempt",
+			succeedOn: 2,
+			expected: []string{
+				"start retry.Do",
+				"attempt 1 err=fail delay=1ms span=retry.Do",
+				"attempt 2 err=<nil> delay=0s span=retry.Do",
+				"result attempts=2 success=true",
+			},
+		},
+		{
+			name:      "exhausted",
+			succeedOn: 0,
+			expected: []string{
+				"start retry.Do",
+				"attempt 1 err=fail delay=1ms span=retry.Do",
+				"attempt 2 err=fail delay=0s span=retry.Do",
+				"result attempts=2 success=false",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tp := &recordingTelemetry{}
+			rc := NewRetry(WithAttempts(2), WithDelay(time.Millisecond), WithTelemetryProvider(tp))
+
+			calls := 0
+			_, err := Do(context.Background(), rc, func() (int, error) {
+				calls++
+				if calls == tc.succeedOn {
+					return 1, nil
+				}
+				return 0, errors.New("fail")
+			})
+
+			if !slices.Equal(tp.events, tc.expected) {
+				t.Errorf("expected events %q, got %q", tc.expected, tp.events)
+			}
+			if len(tp.ended) != 1 || tp.ended[0] != err {
+				t.Errorf("expected the span to end once with %v, got %v", err, tp.ended)
+			}
+		})
+	}
+}
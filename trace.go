@@ -0,0 +1,120 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// TraceEventKind identifies the kind of an event recorded in a Trace.
+type TraceEventKind int
+
+const (
+	EventAttemptStart  TraceEventKind = iota // An attempt is about to call the retry function
+	EventAttemptResult                       // The retry function returned
+	EventDelayStart                          // The delay before the next attempt begins
+	EventDelayEnd                            // The delay ended or was interrupted
+)
+
+// String returns the name of the event kind.
+func (k TraceEventKind) String() string {
+	switch k {
+	case EventAttemptStart:
+		return "attempt start"
+	case EventAttemptResult:
+		return "attempt result"
+	case EventDelayStart:
+		return "delay start"
+	case EventDelayEnd:
+		return "delay end"
+	default:
+		return "unknown"
+	}
+}
+
+// TraceEvent is a single timestamped event of a retry loop.
+type TraceEvent struct {
+	Kind    TraceEventKind // What happened
+	Attempt int            // Attempt number the event belongs to
+	Time    time.Time      // When it happened
+	Err     error          // Attempt error for results, context error for interrupted delays
+	Delay   time.Duration  // Planned delay for delay events
+}
+
+// Trace records every internal event of a retry loop for post-mortem
+// analysis. It is a development and debugging tool: every event is kept in
+// memory, so use it with care in production.
+//
+// Use DoWithTrace() to obtain a Trace.
+type Trace struct {
+	events []TraceEvent // Recorded events in chronological order
+	clock  Clock        // Time source of the event timestamps
+}
+
+// record implements observer by appending an event to the trace.
+func (tr *Trace) record(kind TraceEventKind, attempt int, err error, delay time.Duration) {
+	tr.events = append(tr.events, TraceEvent{
+		Kind:    kind,
+		Attempt: attempt,
+		Time:    tr.clock.Now(),
+		Err:     err,
+		Delay:   delay,
+	})
+}
+
+// Events returns a copy of all recorded events in chronological order.
+func (tr *Trace) Events() []TraceEvent {
+	return append([]TraceEvent(nil), tr.events...)
+}
+
+// Duration returns the time between the first and the last recorded event.
+func (tr *Trace) Duration() time.Duration {
+	if len(tr.events) == 0 {
+		return 0
+	}
+
+	return tr.events[len(tr.events)-1].Time.Sub(tr.events[0].Time)
+}
+
+// Attempts returns the number of attempts that were started.
+func (tr *Trace) Attempts() int {
+	n := 0
+	for _, e := range tr.events {
+		if e.Kind == EventAttemptStart {
+			n++
+		}
+	}
+
+	return n
+}
+
+// DelayDistribution returns the actual duration of every delay between
+// attempts, in order, measured from its start to its end event. Comparing
+// them with the planned delays reveals scheduling overhead.
+func (tr *Trace) DelayDistribution() []time.Duration {
+	var delays []time.Duration
+	var start time.Time
+	for _, e := range tr.events {
+		switch e.Kind {
+		case EventDelayStart:
+			start = e.Time
+		case EventDelayEnd:
+			delays = append(delays, e.Time.Sub(start))
+		}
+	}
+
+	return delays
+}
+
+// DoWithTrace works like Do and additionally returns a Trace of every event
+// of the retry loop. The trace is returned even if the operation fails.
+//
+// Example:
+//
+//	tr, result, err := retry.DoWithTrace(ctx, rc, retryFunc)
+//	log.Printf("%d attempts in %v, delays: %v", tr.Attempts(), tr.Duration(), tr.DelayDistribution())
+func DoWithTrace[T any](ctx context.Context, rc *RetryConfig, fn RetryFunc[T]) (*Trace, T, error) {
+	tr := &Trace{clock: rc.clock}
+	data, err := do(ctx, rc, fn, loopState{observer: tr})
+
+	return tr, data, err
+}
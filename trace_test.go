@@ -0,0 +1,72 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestDoWithTrace verifies that every event of the retry loop is recorded
+// in order and that the analysis helpers summarize them correctly.
+func TestDoWithTrace(t *testing.T) {
+	t.Parallel()
+	rc := NewRetry(WithAttempts(3), WithDelay(10*time.Millisecond))
+	errAttempt := errors.New("attempt error")
+
+	calls := 0
+	tr, result, err := DoWithTrace(context.Background(), rc, func() (string, error) {
+		calls++
+		if calls < 3 {
+			return "", errAttempt
+		}
+		return "success", nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if result != "success" {
+		t.Errorf("expected 'success', got '%s'", result)
+	}
+
+	wantKinds := []TraceEventKind{
+		EventAttemptStart, EventAttemptResult, EventDelayStart, EventDelayEnd,
+		EventAttemptStart, EventAttemptResult, EventDelayStart, EventDelayEnd,
+		EventAttemptStart, EventAttemptResult,
+	}
+	events := tr.Events()
+	if len(events) != len(wantKinds) {
+		t.Fatalf("expected %d events, got %d", len(wantKinds), len(events))
+	}
+	for i, kind := range wantKinds {
+		if events[i].Kind != kind {
+			t.Errorf("event %d: expected %v, got %v", i, kind, events[i].Kind)
+		}
+		if i > 0 && events[i].Time.Before(events[i-1].Time) {
+			t.Errorf("event %d is out of chronological order", i)
+		}
+	}
+
+	if !errors.Is(events[1].Err, errAttempt) || events[9].Err != nil {
+		t.Errorf("expected attempt results to carry the attempt errors, got %v and %v", events[1].Err, events[9].Err)
+	}
+
+	if tr.Attempts() != 3 {
+		t.Errorf("expected 3 attempts, got %d", tr.Attempts())
+	}
+
+	delays := tr.DelayDistribution()
+	if len(delays) != 2 {
+		t.Fatalf("expected 2 delays, got %v", delays)
+	}
+	for i, d := range delays {
+		if d < 10*time.Millisecond {
+			t.Errorf("delay %d: expected at least 10ms, got %v", i+1, d)
+		}
+	}
+
+	if tr.Duration() < 20*time.Millisecond {
+		t.Errorf("expected trace to span at least 20ms, got %v", tr.Duration())
+	}
+}
@@ -0,0 +1,92 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// portDialTimeout bounds a single connection attempt of WaitForPort.
+const portDialTimeout = 100 * time.Millisecond
+
+// WaitForPort blocks until a TCP connection to host:port can be established,
+// retrying according to rc. It is meant for integration tests and service
+// startup, where a dependency needs some time to start listening.
+//
+// Refused connections, timeouts and other transient dial errors are retried.
+// Malformed addresses, such as a port outside 1-65535, are not retried.
+//
+// Example:
+//
+//	rc := retry.NewRetry(retry.WithAttempts(50), retry.WithDelay(200*time.Millisecond))
+//	if err := retry.WaitForPort(ctx, "localhost", 5432, rc); err != nil {
+//	    log.Fatal("database did not start:", err)
+//	}
+func WaitForPort(ctx context.Context, host string, port int, rc *RetryConfig) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port %d", port)
+	}
+
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	_, err := Do(ctx, rc, func() (struct{}, error) {
+		conn, err := net.DialTimeout("tcp", addr, portDialTimeout)
+		if err != nil {
+			var addrErr *net.AddrError
+			if errors.As(err, &addrErr) {
+				return struct{}{}, NonRetryable(err)
+			}
+			return struct{}{}, err
+		}
+
+		return struct{}{}, conn.Close()
+	})
+
+	return err
+}
+
+// WaitForHTTP blocks until a GET request to url returns expectedStatus,
+// retrying according to rc. It is the HTTP counterpart of WaitForPort and is
+// typically used to wait for a readiness endpoint in test setups or init
+// containers.
+//
+// Connection errors, 5xx responses, 429 Too Many Requests and any other
+// unexpected status are retried. Other 4xx responses and malformed URLs are
+// not retried.
+//
+// Example:
+//
+//	rc := retry.NewRetry(retry.WithAttempts(30), retry.WithDelay(time.Second))
+//	err := retry.WaitForHTTP(ctx, "http://localhost:8080/healthz", http.StatusOK, rc)
+func WaitForHTTP(ctx context.Context, url string, expectedStatus int, rc *RetryConfig) error {
+	_, err := Do(ctx, rc, func() (struct{}, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return struct{}{}, NonRetryable(err)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return struct{}{}, err
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode == expectedStatus {
+			return struct{}{}, nil
+		}
+
+		err = fmt.Errorf("unexpected HTTP status %d, want %d", resp.StatusCode, expectedStatus)
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return struct{}{}, NonRetryable(err)
+		}
+
+		return struct{}{}, err
+	})
+
+	return err
+}
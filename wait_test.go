@@ -0,0 +1,139 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestWaitForPortListenerStartsLate verifies that WaitForPort keeps retrying
+// until a listener is started on the port.
+func TestWaitForPortListenerStartsLate(t *testing.T) {
+	t.Parallel()
+	// Reserve a free port, then release it so that dialing is refused
+	// until the listener below is started.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	started := make(chan net.Listener, 1)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			t.Error(err)
+			close(started)
+			return
+		}
+		started <- l
+	}()
+
+	rc := NewRetry(WithAttempts(100), WithDelay(20*time.Millisecond))
+	if err := WaitForPort(context.Background(), "127.0.0.1", port, rc); err != nil {
+		t.Errorf("expected port to become available, got %v", err)
+	}
+
+	if l, ok := <-started; ok {
+		l.Close()
+	}
+}
+
+// TestWaitForPortInvalidAddress verifies that malformed addresses are not
+// retried and that invalid ports are rejected with a plain error before
+// the first attempt.
+func TestWaitForPortInvalidAddress(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name  string
+		host  string
+		port  int
+		plain bool
+	}{
+		{"port out of range", "127.0.0.1", 70000, true},
+		{"zero port", "127.0.0.1", 0, true},
+		{"malformed host", "[::1", 80, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			attempts := 0
+			rc := NewRetry(WithAttempts(5), WithDelay(time.Millisecond), WithOnRetry(func(int, error, time.Duration) {
+				attempts++
+			}))
+
+			err := WaitForPort(context.Background(), tc.host, tc.port, rc)
+			if err == nil || isRetryable(err) == !tc.plain {
+				t.Errorf("expected non-retryable marker %v, got %v", !tc.plain, err)
+			}
+			if attempts != 0 {
+				t.Errorf("expected no retries, got %d", attempts)
+			}
+			if errors.Is(err, ErrExhausted) {
+				t.Errorf("expected no exhaustion, got %v", err)
+			}
+		})
+	}
+}
+
+// TestWaitForHTTP verifies which responses are retried until the expected
+// status is returned.
+func TestWaitForHTTP(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name          string
+		statuses      []int
+		expectedCalls int32
+		expectErr     bool
+	}{
+		{"ready immediately", []int{http.StatusOK}, 1, false},
+		{"5xx then ready", []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK}, 3, false},
+		{"429 then ready", []int{http.StatusTooManyRequests, http.StatusOK}, 2, false},
+		{"4xx not retried", []int{http.StatusNotFound, http.StatusOK}, 1, true},
+		{"exhausted", []int{http.StatusInternalServerError}, 3, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				i := min(int(calls.Add(1)), len(tc.statuses)) - 1
+				w.WriteHeader(tc.statuses[i])
+			}))
+			defer srv.Close()
+
+			rc := NewRetry(WithAttempts(3), WithDelay(time.Millisecond))
+			err := WaitForHTTP(context.Background(), srv.URL, http.StatusOK, rc)
+			if (err != nil) != tc.expectErr {
+				t.Errorf("expected error %t, got %v", tc.expectErr, err)
+			}
+			if got := calls.Load(); got != tc.expectedCalls {
+				t.Errorf("expected %d requests, got %d", tc.expectedCalls, got)
+			}
+		})
+	}
+}
+
+// TestWaitForHTTPConnectionRefused verifies that connection errors are
+// retried until the attempts are exhausted.
+func TestWaitForHTTPConnectionRefused(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	rc := NewRetry(WithAttempts(2), WithDelay(time.Millisecond))
+	if err := WaitForHTTP(context.Background(), url, http.StatusOK, rc); !errors.Is(err, ErrExhausted) {
+		t.Errorf("expected ErrExhausted, got %v", err)
+	}
+}